@@ -0,0 +1,111 @@
+package tnt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// AXIOM is the rule for a Step that states an axiom.  The rule may also
+// name the axiom by number, as in "axiom 3".
+const AXIOM = "axiom"
+
+// AxiomSet is a list of axioms, numbered from 1.
+type AxiomSet []Formula
+
+// Axioms are the five axioms of TNT from Chapter 8.
+var Axioms = AxiomSet{
+	mustParseFormula("∀a:~Sa=0"),
+	mustParseFormula("∀a:(a+0)=a"),
+	mustParseFormula("∀a:∀b:(a+Sb)=S(a+b)"),
+	mustParseFormula("∀a:(a·0)=0"),
+	mustParseFormula("∀a:∀b:(a·Sb)=((a·b)+a)"),
+}
+
+func mustParseFormula(src string) Formula {
+	f, err := ParseFormula(src)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// Find returns the number of the axiom in s that f is, allowing for
+// the quantified Variables to be renamed.
+func (s AxiomSet) Find(f Formula) (int, bool) {
+	for i, axiom := range s {
+		if AlphaEquivalent(f, axiom) {
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
+
+// IsAxiom returns true if f is one of the Axioms of TNT, allowing for
+// the quantified Variables to be renamed.
+func IsAxiom(f Formula) bool {
+	_, ok := Axioms.Find(f)
+	return ok
+}
+
+var (
+	axiomSetsMu sync.RWMutex
+	axiomSets   = map[string]AxiomSet{
+		"tnt": append(AxiomSet(nil), Axioms...),
+	}
+)
+
+// RegisterAxioms makes a copy of an AxiomSet available under the given
+// name, so that tools can check derivations in systems other than TNT.
+// Names are not case sensitive, and "TNT" is registered as a copy of
+// Axioms.  Registering a name twice replaces the earlier AxiomSet.
+// RegisterAxioms may be called while other goroutines look up axioms.
+func RegisterAxioms(name string, set AxiomSet) {
+	axiomSetsMu.Lock()
+	defer axiomSetsMu.Unlock()
+	axiomSets[strings.ToLower(name)] = append(AxiomSet(nil), set...)
+}
+
+// LookupAxioms returns the AxiomSet registered under the given name.
+func LookupAxioms(name string) (AxiomSet, bool) {
+	axiomSetsMu.RLock()
+	defer axiomSetsMu.RUnlock()
+	set, ok := axiomSets[strings.ToLower(name)]
+	return set, ok
+}
+
+// axiomNumber parses the rule of a Step that states an axiom, returning
+// the number of the axiom, or 0 if it is not given.
+func axiomNumber(rule string) (n int, ok bool) {
+	fields := strings.Fields(strings.ToLower(rule))
+	if len(fields) == 0 || fields[0] != AXIOM {
+		return 0, false
+	}
+	switch len(fields) {
+	case 1:
+		return 0, true
+	case 2:
+		n, err := strconv.Atoi(fields[1])
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// checkAxiom verifies that f is the axiom numbered n in s, or any axiom
+// if n is 0.
+func checkAxiom(s AxiomSet, n int, f Formula) error {
+	if n == 0 {
+		if _, ok := s.Find(f); !ok {
+			return fmt.Errorf("%s is not an axiom", f)
+		}
+		return nil
+	}
+	if n < 1 || n > len(s) {
+		return fmt.Errorf("there is no axiom %d", n)
+	}
+	if !AlphaEquivalent(f, s[n-1]) {
+		return fmt.Errorf("axiom %d: expected %s but got %s", n, s[n-1], f)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package tnt
+
+import "testing"
+
+func TestAxioms(t *testing.T) {
+	for _, test := range []struct {
+		formula string
+		number  int
+	}{
+		{"∀a:~Sa=0", 1},
+		{"∀b:~Sb=0", 1},
+		{"∀a:∀b:(a+Sb)=S(a+b)", 3},
+		{"∀b:∀a:(b+Sa)=S(b+a)", 3},
+		{"∀c:∀d:(c·Sd)=((c·d)+c)", 5},
+		{"∀a:∀b:(b+Sa)=S(b+a)", 0},
+		{"(a+0)=a", 0},
+	} {
+		f := mustParse(t, test.formula)
+		n, ok := Axioms.Find(f)
+		if n != test.number || ok != (test.number != 0) {
+			t.Errorf("%s: expected axiom %d but got %d", test.formula, test.number, n)
+		}
+		if IsAxiom(f) != ok {
+			t.Errorf("%s: IsAxiom disagrees with Find", test.formula)
+		}
+	}
+}
+
+func TestLookupAxioms(t *testing.T) {
+	set, ok := LookupAxioms("TNT")
+	if !ok || len(set) != 5 {
+		t.Errorf("expected the axioms of TNT but got %v", set)
+	}
+
+	if &set[0] == &Axioms[0] {
+		t.Errorf("expected TNT to be registered as a copy of Axioms")
+	}
+
+	registered := AxiomSet{mustParse(t, "0=0")}
+	RegisterAxioms("test axioms", registered)
+	registered[0] = mustParse(t, "0=S0")
+	if set, ok := LookupAxioms("Test Axioms"); !ok || set[0].String() != "0=0" {
+		t.Errorf("expected the registered axioms but got %v", set)
+	}
+}
+
+func TestCheckAxiom(t *testing.T) {
+	for _, test := range []struct {
+		axioms  AxiomSet
+		formula string
+		rule    string
+		err     string
+	}{
+		{nil, "∀b:(b+0)=b", "axiom", ""},
+		{nil, "∀b:(b+0)=b", "Axiom 2", ""},
+		{nil, "∀b:(b+0)=b", "axiom 4", "step 1: axiom 4: expected ∀a:(a·0)=0 but got ∀b:(b+0)=b"},
+		{nil, "∀b:(b+0)=b", "axiom 6", "step 1: there is no axiom 6"},
+		{nil, "∀b:(0+b)=b", "axiom", "step 1: ∀b:(0+b)=b is not an axiom"},
+		{AxiomSet{mustParse(t, "0=0")}, "0=0", "axiom 1", ""},
+		{AxiomSet{mustParse(t, "0=0")}, "∀b:(b+0)=b", "axiom", "step 1: ∀b:(b+0)=b is not an axiom"},
+	} {
+		var d Derivation
+		d.Axioms = test.axioms
+		d.Add(mustParse(t, test.formula), test.rule)
+		err := Check(d)
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%s (%s): unexpected error %s", test.formula, test.rule, err)
+		case test.err != "" && (err == nil || err.Error() != test.err):
+			t.Errorf("%s (%s): expected error %q but got %v",
+				test.formula, test.rule, test.err, err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package tnt
+
+// Occurrence is an appearance of a Variable as a Term inside a Formula.
+type Occurrence struct {
+	Variable Variable
+	// Path locates the Variable.
+	Path Path
+	// Binder locates the innermost Quantification of the Variable that
+	// contains this Occurrence, or is nil if the Occurrence is free.
+	Binder Path
+}
+
+// Free returns true if the Occurrence is not bound by any Quantification.
+func (o Occurrence) Free() bool {
+	return o.Binder == nil
+}
+
+// Occurrences returns every Occurrence of a Variable in f, from left to
+// right.
+func Occurrences(f Formula) []Occurrence {
+	var o occurrences
+	o.formula(f, Path{}, nil)
+	return o
+}
+
+type occurrences []Occurrence
+
+// binders maps each quantified Variable in scope to the Path of its
+// innermost Quantification.
+type binders map[Variable]Path
+
+func (b binders) with(v Variable, path Path) binders {
+	scope := make(binders, len(b)+1)
+	for k, p := range b {
+		scope[k] = p
+	}
+	scope[v] = path
+	return scope
+}
+
+func (o *occurrences) formula(f Formula, path Path, scope binders) {
+	switch f := f.(type) {
+	case Atom:
+		o.term(f.Left, path.Child(LEFT), scope)
+		o.term(f.Right, path.Child(RIGHT), scope)
+	case Negation:
+		o.formula(f.Formula, path.Child(BODY), scope)
+	case Compound:
+		o.formula(f.Left, path.Child(LEFT), scope)
+		o.formula(f.Right, path.Child(RIGHT), scope)
+	case Quantification:
+		o.formula(f.Formula, path.Child(BODY), scope.with(f.Variable, path))
+	}
+}
+
+func (o *occurrences) term(t Term, path Path, scope binders) {
+	switch t := t.(type) {
+	case Variable:
+		*o = append(*o, Occurrence{
+			Variable: t,
+			Path:     path,
+			Binder:   scope[t],
+		})
+	case Successor:
+		o.term(t.Term, path.Child(BODY), scope)
+	case CompoundTerm:
+		o.term(t.Left, path.Child(LEFT), scope)
+		o.term(t.Right, path.Child(RIGHT), scope)
+	}
+}
@@ -0,0 +1,87 @@
+package tnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBinding(t *testing.T) {
+	type testCase struct {
+		Input      string
+		Free       VariableSet
+		Open       bool
+		WellFormed bool
+	}
+
+	for _, test := range []testCase{
+		{"a=b", NewVariableSetString("a", "b"), true, true},
+		{"∀a:a=b", NewVariableSetString("b"), true, true},
+		{"∀a:∃b:(a+b)=0", NewVariableSetString(), false, true},
+		{"~∀a:∃b:(a+b)=c", NewVariableSetString("c"), true, true},
+		{"∃a:∀a:a=0", NewVariableSetString(), false, false},
+		{"∀c:<∃a:a=c∧0=0>", NewVariableSetString(), false, true},
+		{"∀c:∃d:<∃a:a=c∧d=0>", NewVariableSetString(), false, true},
+		{"∀a:<a=0∧∃a:a=S0>", NewVariableSetString(), false, false},
+		{"<∀a:a=b∧∃b:Sa=b>", NewVariableSetString("a", "b"), true, false},
+		{"∀b:<∀a:a=b∧∃c:c=b>", NewVariableSetString(), false, true},
+		{"~∀a:∃b:<a=b∨∀c:c=d>", NewVariableSetString("d"), true, true},
+		{"∃d:∀a:∃b:<a=b∨∀c:c=d>", NewVariableSetString(), false, true},
+		{"∃d:∀a:∃b:<a=b∨∀d:d=c>", NewVariableSetString("c"), true, false},
+	} {
+		formula, err := ParseFormula(test.Input)
+		if err != nil {
+			t.Errorf("error parsing %q: %s", test.Input, err)
+			continue
+		}
+
+		if got := formula.FreeVariables(); !reflect.DeepEqual(got, test.Free) {
+			t.Errorf("%q: expected free variables %v but got %v",
+				test.Input, test.Free, got)
+		}
+		if got := formula.Open(); got != test.Open {
+			t.Errorf("%q: expected open %t but got %t",
+				test.Input, test.Open, got)
+		}
+		if got := formula.WellFormed(); got != test.WellFormed {
+			t.Errorf("%q: expected well formed %t but got %t: %v",
+				test.Input, test.WellFormed, got, CheckWellFormed(formula))
+		}
+
+		free := NewVariableSet()
+		for _, o := range Occurrences(formula) {
+			if o.Free() {
+				free[o.Variable] = struct{}{}
+			}
+		}
+		if !reflect.DeepEqual(free, test.Free) {
+			t.Errorf("%q: expected free occurrences of %v but got %v",
+				test.Input, test.Free, free)
+		}
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	for input, expected := range map[string][]Occurrence{
+		"0=S0": nil,
+		"<∀a:a=b∧∃b:Sa=b>": {
+			{"a", Path{LEFT, BODY, LEFT}, Path{LEFT}},
+			{"b", Path{LEFT, BODY, RIGHT}, nil},
+			{"a", Path{RIGHT, BODY, LEFT, BODY}, nil},
+			{"b", Path{RIGHT, BODY, RIGHT}, Path{RIGHT}},
+		},
+		"∀a:<a=0∧∃a:(0+a)=S0>": {
+			{"a", Path{BODY, LEFT, LEFT}, Path{}},
+			{"a", Path{BODY, RIGHT, BODY, LEFT, RIGHT}, Path{BODY, RIGHT}},
+		},
+	} {
+		formula, err := ParseFormula(input)
+		if err != nil {
+			t.Errorf("error parsing %q: %s", input, err)
+			continue
+		}
+		if got := Occurrences(formula); !reflect.DeepEqual(got, expected) {
+			t.Errorf("%q: expected occurrences %+v but got %+v",
+				input, expected, got)
+		}
+	}
+}
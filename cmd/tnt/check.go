@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jeremyhuiskamp/tnt"
+	"github.com/jeremyhuiskamp/tnt/derivation"
+)
+
+func check(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("tnt check", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	axiomsName := flags.String("axioms", "TNT", "name or file of the axioms that derivations may state")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	axioms, ok := loadAxioms("tnt check", *axiomsName, stderr)
+	if !ok {
+		return 2
+	}
+
+	inputs, err := readInputs(flags.Args(), stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "tnt check: %s\n", err)
+		return 1
+	}
+
+	status := 0
+	for _, in := range inputs {
+		d, positions, err := derivation.ParsePositions(strings.NewReader(in.src))
+		if err != nil {
+			printError(stderr, in.name, err)
+			status = 1
+			continue
+		}
+		d.Axioms = axioms
+		if err := tnt.Check(d); err != nil {
+			// Report the line of the step, as for errors in the layout.
+			var stepErr *tnt.StepError
+			if errors.As(err, &stepErr) {
+				fmt.Fprintf(stderr, "%s:%s: %s\n",
+					in.name, positions[stepErr.Step-1].Number, err)
+			} else {
+				fmt.Fprintf(stderr, "%s: %s\n", in.name, err)
+			}
+			status = 1
+		}
+	}
+	return status
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jeremyhuiskamp/tnt"
+	"github.com/jeremyhuiskamp/tnt/token"
+)
+
+func format(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("tnt fmt", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	write := flags.Bool("w", false, "write the result to the file instead of stdout")
+	diff := flags.Bool("d", false, "display diffs instead of rewriting formulas")
+	ascii := flags.Bool("ascii", false, "use ASCII notation instead of Unicode")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *write && flags.NArg() == 0 {
+		fmt.Fprintln(stderr, "tnt fmt: cannot use -w with standard input")
+		return 2
+	}
+	if *write && *diff {
+		fmt.Fprintln(stderr, "tnt fmt: cannot use -w with -d")
+		return 2
+	}
+
+	inputs, err := readInputs(flags.Args(), stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "tnt fmt: %s\n", err)
+		return 1
+	}
+
+	p := tnt.Printer{}
+	if *ascii {
+		p.Dialect = token.ASCII
+	}
+
+	status := 0
+	for _, in := range inputs {
+		if isDerivation(in.src) {
+			fmt.Fprintf(stderr, "tnt fmt: %s holds a derivation, not formulas\n", in.name)
+			status = 1
+			continue
+		}
+		formatted, err := formatSource(p, in.src)
+		if err != nil {
+			printError(stderr, in.name, err)
+			status = 1
+			continue
+		}
+		switch {
+		case *diff:
+			writeDiff(stdout, in.name, in.src, formatted)
+		case *write:
+			if formatted == in.src {
+				continue
+			}
+			info, err := os.Stat(in.name)
+			if err == nil {
+				err = os.WriteFile(in.name, []byte(formatted), info.Mode().Perm())
+			}
+			if err != nil {
+				fmt.Fprintf(stderr, "tnt fmt: %s\n", err)
+				status = 1
+			}
+		default:
+			io.WriteString(stdout, formatted)
+		}
+	}
+	return status
+}
+
+// isDerivation reports whether src holds a derivation rather than one
+// formula per line, judging by whether its first line that is not blank
+// or a comment starts with a step number.  No formula starts with a
+// digit other than 0.
+func isDerivation(src string) bool {
+	for _, line := range strings.Split(src, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			return trimmed[0] >= '1' && trimmed[0] <= '9'
+		}
+	}
+	return false
+}
+
+// formatSource rewrites each line of src holding a formula in canonical
+// notation, keeping its indentation and line ending.  Blank lines and
+// lines starting with # are kept as they are.
+func formatSource(p tnt.Printer, src string) (string, error) {
+	lines := strings.Split(src, "\n")
+	err := parseLines(src, func(i int, f tnt.Formula) {
+		line := lines[i]
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		lines[i] = indent + p.Formula(f)
+		if strings.HasSuffix(line, "\r") {
+			lines[i] += "\r"
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// parseLines parses each line of src that holds a formula, calling f
+// with the index of the line and its formula.  Blank lines and lines
+// starting with # are skipped.  The positions of errors are in src.
+func parseLines(src string, f func(i int, formula tnt.Formula)) error {
+	offset := 0
+	for i, line := range strings.Split(src, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			formula, err := tnt.ParseFormula(line)
+			if err != nil {
+				if perr, ok := err.(*tnt.ParseError); ok {
+					perr.Src = src
+					perr.Pos.Line = i + 1
+					perr.Pos.Offset += offset
+				}
+				return err
+			}
+			f(i, formula)
+		}
+		// Offsets count runes, like the columns of positions.
+		offset += utf8.RuneCountInString(line) + 1
+	}
+	return nil
+}
+
+// writeDiff writes the lines that differ between before and after, which
+// have the same number of lines, in unified diff format.
+func writeDiff(w io.Writer, name, before, after string) {
+	if before == after {
+		return
+	}
+	fmt.Fprintf(w, "--- %s\n+++ %s\n", name, name)
+	beforeLines := strings.Split(before, "\n")
+	afterLines := strings.Split(after, "\n")
+	for i := range beforeLines {
+		if beforeLines[i] != afterLines[i] {
+			fmt.Fprintf(w, "@@ -%d +%d @@\n-%s\n+%s\n",
+				i+1, i+1, beforeLines[i], afterLines[i])
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jeremyhuiskamp/tnt/lsp"
+)
+
+func languageServer(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) != 0 {
+		fmt.Fprintln(stderr, "usage: tnt lsp")
+		return 2
+	}
+	if err := lsp.Serve(stdin, stdout); err != nil {
+		fmt.Fprintf(stderr, "tnt lsp: %s\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,150 @@
+/*
+Command tnt works with formulas and derivations of Typographical Number
+Theory.
+
+Usage:
+
+	tnt parse [formula ...]
+	tnt fmt [-w | -d] [-ascii] [file ...]
+	tnt check [-axioms name|file] [file ...]
+	tnt repl [-axioms name|file]
+	tnt lsp
+	tnt serve [-addr host:port] [-axioms name|file]
+
+The parse command describes each formula given as an argument, or each
+line of standard input.  The fmt command rewrites formulas, one per line,
+in canonical notation; it refuses derivations, whose layout it would not
+keep.  The check command verifies derivation files, reporting the line
+of the first invalid step.  The repl command builds a derivation
+interactively; see package repl.  The lsp command runs a language server
+on standard input and output; see package lsp.  The serve command serves
+a web playground; see package playground.
+
+Without files, fmt and check read standard input.  The exit status is 1
+if any formula or derivation is invalid, and 2 for incorrect usage.
+
+The -axioms flag of check, repl and serve selects the axioms that steps
+may state.  It is the name of a registered set of axioms, TNT by
+default, or else the name of a file holding one axiom per line, laid out
+as read by fmt:
+
+	# TNT with a shortcut
+	Aa:~Sa=0
+	Aa:(a+0)=a
+	Aa:Ab:(a+Sb)=S(a+b)
+	Aa:(a*0)=0
+	Aa:Ab:(a*Sb)=((a*b)+a)
+	Aa:(0+a)=a
+*/
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jeremyhuiskamp/tnt"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// command is a subcommand of tnt.  It returns the exit status.
+type command func(args []string, stdin io.Reader, stdout, stderr io.Writer) int
+
+var commands = map[string]command{
+	"parse": parse,
+	"fmt":   format,
+	"check": check,
+	"repl":  interactive,
+	"lsp":   languageServer,
+	"serve": serve,
+}
+
+const usage = `usage: tnt <command> [arguments]
+
+commands:
+	parse   describe formulas
+	fmt     format formulas in canonical notation
+	check   verify derivations
+	repl    build a derivation interactively
+	lsp     run a language server for editors
+	serve   serve a web playground
+`
+
+// run runs the tnt command with the given arguments, not including the
+// program name, and returns the exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "tnt: unknown command %q\n", args[0])
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	return cmd(args[1:], stdin, stdout, stderr)
+}
+
+// input is a named source of text.
+type input struct {
+	name string
+	src  string
+}
+
+// readInputs reads the named files, or stdin if there are none.
+func readInputs(names []string, stdin io.Reader) ([]input, error) {
+	if len(names) == 0 {
+		src, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, err
+		}
+		return []input{{name: "<stdin>", src: string(src)}}, nil
+	}
+
+	inputs := make([]input, len(names))
+	for i, name := range names {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		inputs[i] = input{name: name, src: string(src)}
+	}
+	return inputs, nil
+}
+
+// loadAxioms returns the axioms registered under name, or else those in
+// the file with that name.  If there are none, it reports why to stderr
+// on behalf of the command cmd.
+func loadAxioms(cmd, name string, stderr io.Writer) (tnt.AxiomSet, bool) {
+	if axioms, ok := tnt.LookupAxioms(name); ok {
+		return axioms, true
+	}
+	src, err := os.ReadFile(name)
+	if os.IsNotExist(err) {
+		fmt.Fprintf(stderr, "%s: unknown axioms %q\n", cmd, name)
+		return nil, false
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %s\n", cmd, err)
+		return nil, false
+	}
+
+	var axioms tnt.AxiomSet
+	err = parseLines(string(src), func(_ int, f tnt.Formula) {
+		axioms = append(axioms, f)
+	})
+	if err != nil {
+		printError(stderr, name, err)
+		return nil, false
+	}
+	if len(axioms) == 0 {
+		// A nil AxiomSet would mean the axioms of TNT.
+		fmt.Fprintf(stderr, "%s: no axioms in %s\n", cmd, name)
+		return nil, false
+	}
+	return axioms, true
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhuiskamp/tnt"
+)
+
+// runTest runs tnt with the given arguments and stdin, returning the
+// exit status and output.
+func runTest(stdin string, args ...string) (status int, stdout, stderr string) {
+	var out, errOut bytes.Buffer
+	status = run(args, strings.NewReader(stdin), &out, &errOut)
+	return status, out.String(), errOut.String()
+}
+
+func TestUsage(t *testing.T) {
+	if status, _, stderr := runTest(""); status != 2 || !strings.HasPrefix(stderr, "usage:") {
+		t.Errorf("expected usage and status 2 but got %d: %s", status, stderr)
+	}
+	if status, _, stderr := runTest("", "prove"); status != 2 ||
+		!strings.HasPrefix(stderr, `tnt: unknown command "prove"`) {
+		t.Errorf("expected unknown command and status 2 but got %d: %s", status, stderr)
+	}
+}
+
+func TestParse(t *testing.T) {
+	status, stdout, stderr := runTest("", "parse", "∀a:<a=b∨~S0=a>")
+	expected := `∀a:<a=b∨~S0=a>
+  Quantification FOR_ALL a
+    Compound OR
+      Atom
+        Variable a
+        Variable b
+      Negation
+        Atom
+          Numeral 1
+          Variable a
+variables:      [a b]
+free variables: [b]
+open:           true
+well formed:    true
+`
+	if status != 0 || stdout != expected || stderr != "" {
+		t.Errorf("expected status 0 and\n%s\nbut got %d and\n%s%s", expected, status, stdout, stderr)
+	}
+
+	status, stdout, _ = runTest("∀a:b=b\n", "parse")
+	if status != 0 || !strings.Contains(stdout, "well formed:    false\n  /: a does not occur") {
+		t.Errorf("expected well-formedness diagnostic but got %d:\n%s", status, stdout)
+	}
+
+	status, _, stderr = runTest("", "parse", "<a=b∨c=d")
+	expected = "1:9: expected > but got EOF\n<a=b∨c=d\n        ^\n"
+	if status != 1 || stderr != expected {
+		t.Errorf("expected status 1 and\n%s\nbut got %d and\n%s", expected, status, stderr)
+	}
+}
+
+func TestFmt(t *testing.T) {
+	src := "# identities\nS S 0 = (S0 + S0)\n\n  ~ Aa: a=a\n"
+
+	status, stdout, stderr := runTest(src, "fmt")
+	if expected := "# identities\nSS0=(S0+S0)\n\n  ~∀a:a=a\n"; status != 0 || stdout != expected {
+		t.Errorf("expected status 0 and %q but got %d and %q %s", expected, status, stdout, stderr)
+	}
+
+	status, stdout, _ = runTest(src, "fmt", "-ascii")
+	if expected := "# identities\nSS0=(S0+S0)\n\n  ~Aa:a=a\n"; status != 0 || stdout != expected {
+		t.Errorf("expected status 0 and %q but got %d and %q", expected, status, stdout)
+	}
+
+	status, stdout, _ = runTest(src, "fmt", "-d")
+	expected := "--- <stdin>\n+++ <stdin>\n" +
+		"@@ -2 +2 @@\n-S S 0 = (S0 + S0)\n+SS0=(S0+S0)\n" +
+		"@@ -4 +4 @@\n-  ~ Aa: a=a\n+  ~∀a:a=a\n"
+	if status != 0 || stdout != expected {
+		t.Errorf("expected status 0 and %q but got %d and %q", expected, status, stdout)
+	}
+
+	status, _, stderr = runTest("0=0\na=_\n", "fmt")
+	if expected := "<stdin>:2:3: expected 0, S, VARIABLE or ( but got ILLEGAL \"_\"\na=_\n  ^\n"; status != 1 || stderr != expected {
+		t.Errorf("expected status 1 and %q but got %d and %q", expected, status, stderr)
+	}
+
+	if status, _, _ := runTest(src, "fmt", "-w"); status != 2 {
+		t.Errorf("expected status 2 for -w on stdin but got %d", status)
+	}
+	if status, _, stderr := runTest(src, "fmt", "-w", "-d", "formulas.txt"); status != 2 ||
+		stderr != "tnt fmt: cannot use -w with -d\n" {
+		t.Errorf("expected status 2 for -w with -d but got %d: %s", status, stderr)
+	}
+
+	status, stdout, _ = runTest("# crlf\r\nS S 0 = 0\r\n  a = a\r\n", "fmt")
+	if expected := "# crlf\r\nSS0=0\r\n  a=a\r\n"; status != 0 || stdout != expected {
+		t.Errorf("expected status 0 and %q but got %d and %q", expected, status, stdout)
+	}
+
+	status, stdout, stderr = runTest("# proof\n1  ∀a:(a+0)=a  (axiom 2)\n", "fmt")
+	if expected := "tnt fmt: <stdin> holds a derivation, not formulas\n"; status != 1 ||
+		stdout != "" || stderr != expected {
+		t.Errorf("expected status 1 and %q but got %d and %q %q", expected, status, stdout, stderr)
+	}
+
+	_, err := formatSource(tnt.Printer{}, "∀a:a=a\na=_\n")
+	if perr, ok := err.(*tnt.ParseError); !ok || perr.Pos.Offset != 9 {
+		t.Errorf("expected an error at offset 9 but got %#v", err)
+	}
+}
+
+func TestFmtWrite(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "formulas.txt")
+	if err := os.WriteFile(name, []byte("a = a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if status, stdout, stderr := runTest("", "fmt", "-w", name); status != 0 || stdout != "" {
+		t.Errorf("expected status 0 and no output but got %d: %s%s", status, stdout, stderr)
+	}
+	if src, _ := os.ReadFile(name); string(src) != "a=a\n" {
+		t.Errorf("expected file to be rewritten but got %q", src)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.tnt")
+	invalid := filepath.Join(dir, "invalid.tnt")
+	os.WriteFile(valid, []byte(`
+1  ∀a:(a+0)=a    (axiom 2)
+2  (S0+0)=S0     (specification: 1)
+`), 0644)
+	os.WriteFile(invalid, []byte(`
+1  ∀a:(a+0)=a    (axiom 2)
+2  (S0+0)=0      (specification: 1)
+`), 0644)
+
+	if status, _, stderr := runTest("", "check", valid); status != 0 || stderr != "" {
+		t.Errorf("expected status 0 but got %d: %s", status, stderr)
+	}
+
+	status, _, stderr := runTest("", "check", valid, invalid)
+	expected := invalid + ":3:1: step 2: specification: (S0+0)=0 is not (a+0)=a with a term in place of a\n"
+	if status != 1 || stderr != expected {
+		t.Errorf("expected status 1 and %q but got %d and %q", expected, status, stderr)
+	}
+
+	status, _, stderr = runTest("1  0=0  (axiom 1)\n", "check", "-axioms", "none")
+	if status != 2 || stderr != "tnt check: unknown axioms \"none\"\n" {
+		t.Errorf("expected unknown axioms but got %d: %s", status, stderr)
+	}
+
+	axioms := filepath.Join(dir, "axioms.txt")
+	os.WriteFile(axioms, []byte("# just one\nAa:(a+0)=a\n"), 0644)
+	status, _, stderr = runTest("1  ∀a:(a+0)=a  (axiom 1)\n", "check", "-axioms", axioms)
+	if status != 0 || stderr != "" {
+		t.Errorf("expected status 0 but got %d: %s", status, stderr)
+	}
+
+	os.WriteFile(axioms, []byte("# none\n"), 0644)
+	status, _, stderr = runTest("", "check", "-axioms", axioms)
+	if expected := "tnt check: no axioms in " + axioms + "\n"; status != 2 || stderr != expected {
+		t.Errorf("expected status 2 and %q but got %d and %q", expected, status, stderr)
+	}
+
+	os.WriteFile(axioms, []byte("Aa:(a+0)=a\n0=\n"), 0644)
+	status, _, stderr = runTest("", "check", "-axioms", axioms)
+	if expected := axioms + ":2:3: expected 0, S, VARIABLE or ( but got EOF\n0=\n  ^\n"; status != 2 || stderr != expected {
+		t.Errorf("expected status 2 and %q but got %d and %q", expected, status, stderr)
+	}
+
+	status, _, stderr = runTest("1  0=  (axiom 1)\n", "check")
+	if status != 1 || !strings.HasPrefix(stderr, "<stdin>:1:6: expected 0, S, VARIABLE or ( but got EOF") {
+		t.Errorf("expected parse error but got %d: %s", status, stderr)
+	}
+}
+
+func TestRepl(t *testing.T) {
+	status, stdout, _ := runTest("∀a:(a+0)=a  (axiom 2)\n", "repl")
+	if expected := "tnt> 1  ∀a:(a+0)=a    (axiom 2)\ntnt> \n"; status != 0 || stdout != expected {
+		t.Errorf("expected status 0 and %q but got %d and %q", expected, status, stdout)
+	}
+}
+
+func TestLSP(t *testing.T) {
+	request := `{"jsonrpc":"2.0","id":1,"method":"shutdown"}`
+	exit := `{"jsonrpc":"2.0","method":"exit"}`
+	stdin := fmt.Sprintf("Content-Length: %d\r\n\r\n%sContent-Length: %d\r\n\r\n%s",
+		len(request), request, len(exit), exit)
+
+	status, stdout, stderr := runTest(stdin, "lsp")
+	response := `{"jsonrpc":"2.0","id":1,"result":null}`
+	expected := fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(response), response)
+	if status != 0 || stdout != expected {
+		t.Errorf("expected status 0 and %q but got %d and %q %s", expected, status, stdout, stderr)
+	}
+}
+
+func TestServeUsage(t *testing.T) {
+	status, _, stderr := runTest("", "serve", "-axioms", "none")
+	if status != 2 || stderr != "tnt serve: unknown axioms \"none\"\n" {
+		t.Errorf("expected unknown axioms but got %d: %s", status, stderr)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jeremyhuiskamp/tnt"
+)
+
+func parse(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	formulas := args
+	if len(formulas) == 0 {
+		src, err := io.ReadAll(stdin)
+		if err != nil {
+			fmt.Fprintf(stderr, "tnt parse: %s\n", err)
+			return 1
+		}
+		for _, line := range strings.Split(string(src), "\n") {
+			if strings.TrimSpace(line) != "" {
+				formulas = append(formulas, line)
+			}
+		}
+	}
+
+	status := 0
+	for i, src := range formulas {
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+		f, err := tnt.ParseFormula(src)
+		if err != nil {
+			printError(stderr, "", err)
+			status = 1
+			continue
+		}
+		describe(stdout, f)
+	}
+	return status
+}
+
+// describe writes the syntax tree of f and its properties.
+func describe(w io.Writer, f tnt.Formula) {
+	fmt.Fprintln(w, f)
+	for _, part := range tnt.Parts(f) {
+		fmt.Fprintf(w, "%s%s\n", strings.Repeat("  ", len(part.Path)+1), label(part.Node))
+	}
+	fmt.Fprintf(w, "variables:      %s\n", f.Variables())
+	fmt.Fprintf(w, "free variables: %s\n", f.FreeVariables())
+	fmt.Fprintf(w, "open:           %t\n", f.Open())
+	fmt.Fprintf(w, "well formed:    %t\n", f.WellFormed())
+	for _, d := range tnt.CheckWellFormed(f) {
+		fmt.Fprintf(w, "  %s\n", d)
+	}
+}
+
+// label describes a node of a syntax tree, without its children.
+func label(n tnt.Node) string {
+	switch n := n.(type) {
+	case tnt.Numeral:
+		return fmt.Sprintf("Numeral %d", int(n))
+	case tnt.Variable:
+		return fmt.Sprintf("Variable %s", n)
+	case tnt.Successor:
+		return fmt.Sprintf("Successor %d", n.Quantity)
+	case tnt.CompoundTerm:
+		return fmt.Sprintf("CompoundTerm %s", n.Kind)
+	case tnt.Atom:
+		return "Atom"
+	case tnt.Negation:
+		return "Negation"
+	case tnt.Compound:
+		return fmt.Sprintf("Compound %s", n.Kind)
+	case tnt.Quantification:
+		return fmt.Sprintf("Quantification %s %s", n.Kind, n.Variable)
+	}
+	return fmt.Sprintf("%T", n)
+}
+
+// printError writes err, whose message starts with a position, prefixed
+// by name if it is not empty.  Parse errors are followed by an excerpt
+// of the source.
+func printError(w io.Writer, name string, err error) {
+	if name != "" {
+		fmt.Fprintf(w, "%s:", name)
+	}
+	fmt.Fprintln(w, err)
+	var perr *tnt.ParseError
+	if errors.As(err, &perr) {
+		if excerpt := perr.Excerpt(); excerpt != "" {
+			fmt.Fprintln(w, excerpt)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/jeremyhuiskamp/tnt/repl"
+)
+
+func interactive(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("tnt repl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	axiomsName := flags.String("axioms", "TNT", "name or file of the axioms that steps may state")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	axioms, ok := loadAxioms("tnt repl", *axiomsName, stderr)
+	if !ok {
+		return 2
+	}
+
+	var s repl.Session
+	s.Derivation.Axioms = axioms
+	if err := repl.Run(&s, stdin, stdout); err != nil {
+		fmt.Fprintf(stderr, "tnt repl: %s\n", err)
+		return 1
+	}
+	return 0
+}
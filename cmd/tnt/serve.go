@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/jeremyhuiskamp/tnt/playground"
+)
+
+func serve(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("tnt serve", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+	axiomsName := flags.String("axioms", "TNT", "name or file of the axioms that derivations may state")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	axioms, ok := loadAxioms("tnt serve", *axiomsName, stderr)
+	if !ok {
+		return 2
+	}
+
+	server := &http.Server{
+		Addr:    *addr,
+		Handler: playground.New(axioms),
+		// Slow clients must not hold connections open indefinitely.
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+	fmt.Fprintf(stdout, "serving the playground at http://%s/\n", *addr)
+	if err := server.ListenAndServe(); err != nil {
+		fmt.Fprintf(stderr, "tnt serve: %s\n", err)
+		return 1
+	}
+	return 0
+}
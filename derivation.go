@@ -0,0 +1,293 @@
+package tnt
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Step is one numbered line of a Derivation.
+type Step struct {
+	// Formula is the theorem produced by the Step, or nil if Rule is
+	// PUSH or POP.
+	Formula Formula
+	// Rule is the name of the rule of inference that produced Formula.
+	Rule string
+	// Premises are the numbers of the earlier Steps that Rule was
+	// applied to.
+	Premises []int
+}
+
+// Derivation is a sequence of Steps, each of which follows from earlier
+// Steps by a rule of inference.  Steps are numbered from 1.
+type Derivation struct {
+	Steps []Step `json:"steps"`
+	// Axioms are the axioms that Steps may state by the AXIOM rule.  If
+	// nil, the Axioms of TNT are used.
+	Axioms AxiomSet `json:"axioms,omitempty"`
+}
+
+// Add appends a Step to d and returns its number.
+func (d *Derivation) Add(f Formula, rule string, premises ...int) int {
+	d.Steps = append(d.Steps, Step{
+		Formula:  f,
+		Rule:     rule,
+		Premises: premises,
+	})
+	return len(d.Steps)
+}
+
+// Step returns the Step with the given number.
+func (d Derivation) Step(n int) (Step, bool) {
+	if n < 1 || n > len(d.Steps) {
+		return Step{}, false
+	}
+	return d.Steps[n-1], true
+}
+
+// Context describes the part of a Derivation in which a Rule is
+// applied.
+type Context struct {
+	// Fantasies are the premises of the fantasies that enclose the
+	// Step, outermost first.
+	Fantasies []Formula
+}
+
+// Rule checks that conclusion follows from premises by a rule of
+// inference.  If not, the returned error explains why.
+type Rule func(ctx Context, premises []Formula, conclusion Formula) error
+
+// namedRule is a Rule with the name it was registered under.
+type namedRule struct {
+	name string
+	rule Rule
+}
+
+var (
+	rulesMu sync.RWMutex
+	rules   = make(map[string]namedRule)
+)
+
+// RegisterRule makes a Rule available to Check under the given name.
+// Names are not case sensitive.  Registering a name twice replaces the
+// earlier Rule.  RegisterRule may be called while other goroutines run
+// Check.
+func RegisterRule(name string, rule Rule) {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	rules[strings.ToLower(name)] = namedRule{name: name, rule: rule}
+}
+
+// LookupRule returns the Rule registered under the given name.
+func LookupRule(name string) (Rule, bool) {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+	r, ok := rules[strings.ToLower(name)]
+	return r.rule, ok
+}
+
+// RuleNames returns the names of the registered Rules, as they were
+// registered, in alphabetical order.  The rules that Check handles
+// itself, such as PREMISE, are not included.
+func RuleNames() []string {
+	rulesMu.RLock()
+	names := make([]string, 0, len(rules))
+	for _, r := range rules {
+		names = append(names, r.name)
+	}
+	rulesMu.RUnlock()
+	sort.Slice(names, func(i, j int) bool {
+		return strings.ToLower(names[i]) < strings.ToLower(names[j])
+	})
+	return names
+}
+
+// StepError describes a Step of a Derivation that does not follow from
+// its premises.
+type StepError struct {
+	Step int
+	Err  error
+}
+
+func (e *StepError) Error() string {
+	return fmt.Sprintf("step %d: %s", e.Step, e.Err)
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// Check verifies that every Step of d follows from the Steps it refers
+// to by its Rule, and that every Formula is WellFormed.  The first
+// invalid Step is reported as a *StepError.
+//
+// Steps may only refer to earlier Steps in the same fantasy, except by
+// the CARRY_OVER rule.  A Derivation may end inside a fantasy.
+//
+// A Step by the AXIOM rule must state one of d.Axioms, and if the rule
+// gives a number, the axiom with that number.
+func Check(d Derivation) error {
+	var c checker
+	c.scopes = make([]*scope, len(d.Steps)+1)
+	c.current = &scope{}
+	for i, step := range d.Steps {
+		if err := c.check(d, i+1, step); err != nil {
+			return &StepError{Step: i + 1, Err: err}
+		}
+	}
+	return nil
+}
+
+// checker tracks the fantasies of a Derivation being checked.
+type checker struct {
+	// scopes holds the fantasy of each Step with a Formula, by number
+	scopes []*scope
+	// current is the fantasy of the Step being checked
+	current *scope
+	// popped is the fantasy most recently left
+	popped *scope
+}
+
+func (c *checker) check(d Derivation, n int, step Step) error {
+	// The names of the built-in rules are matched without regard to
+	// case, as registered Rules are.
+	rule := strings.ToLower(step.Rule)
+	var previous string
+	if n > 1 {
+		previous = strings.ToLower(d.Steps[n-2].Rule)
+	}
+
+	switch rule {
+	case PUSH, POP:
+		if step.Formula != nil {
+			return fmt.Errorf("unexpected formula %s", step.Formula)
+		}
+		if len(step.Premises) != 0 {
+			return fmt.Errorf("unexpected premises")
+		}
+		if rule == PUSH {
+			if previous == PUSH {
+				return fmt.Errorf("the first step of a fantasy must be its %s", PREMISE)
+			}
+			c.current = &scope{parent: c.current}
+			return nil
+		}
+		if c.current.parent == nil {
+			return fmt.Errorf("not in a fantasy")
+		}
+		if c.current.premise == nil {
+			return fmt.Errorf("fantasy has no premise")
+		}
+		c.popped, c.current = c.current, c.current.parent
+		return nil
+	}
+
+	if step.Formula == nil {
+		return fmt.Errorf("missing formula")
+	}
+	if diags := CheckWellFormed(step.Formula); len(diags) != 0 {
+		return fmt.Errorf("%s is not well formed: %s", step.Formula, diags[0])
+	}
+	if previous == PUSH && rule != PREMISE {
+		return fmt.Errorf("the first step of a fantasy must be its %s", PREMISE)
+	}
+
+	var err error
+	if axiom, ok := axiomNumber(step.Rule); ok {
+		err = c.checkAxiom(d, axiom, step)
+	} else {
+		switch rule {
+		case PREMISE:
+			err = c.checkPremise(step, previous)
+		case CARRY_OVER:
+			err = c.checkCarryOver(d, n, step)
+		case FANTASY:
+			err = c.checkFantasy(step, previous)
+		default:
+			err = c.checkRule(d, n, step)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	c.scopes[n] = c.current
+	c.current.last = step.Formula
+	return nil
+}
+
+func (c *checker) checkPremise(step Step, previous string) error {
+	if previous != PUSH {
+		return fmt.Errorf("a %s must directly follow [", PREMISE)
+	}
+	if len(step.Premises) != 0 {
+		return fmt.Errorf("unexpected premises")
+	}
+	c.current.premise = step.Formula
+	return nil
+}
+
+func (c *checker) checkAxiom(d Derivation, axiom int, step Step) error {
+	if len(step.Premises) != 0 {
+		return fmt.Errorf("unexpected premises")
+	}
+	axioms := d.Axioms
+	if axioms == nil {
+		axioms = Axioms
+	}
+	return checkAxiom(axioms, axiom, step.Formula)
+}
+
+func (c *checker) checkCarryOver(d Derivation, n int, step Step) error {
+	if c.current.parent == nil {
+		return fmt.Errorf("%s: not in a fantasy", CARRY_OVER)
+	}
+	if len(step.Premises) != 1 {
+		return fmt.Errorf("%s: expected 1 premise but got %d",
+			CARRY_OVER, len(step.Premises))
+	}
+	p := step.Premises[0]
+	if p < 1 || p >= n {
+		return fmt.Errorf("premise %d is not an earlier step", p)
+	}
+	if c.scopes[p] != c.current.parent {
+		return fmt.Errorf("%s: step %d is not in the enclosing fantasy",
+			CARRY_OVER, p)
+	}
+	return expectConclusion(step.Formula, d.Steps[p-1].Formula)
+}
+
+func (c *checker) checkFantasy(step Step, previous string) error {
+	if previous != POP {
+		return fmt.Errorf("%s: must directly follow ]", FANTASY)
+	}
+	if len(step.Premises) != 0 {
+		return fmt.Errorf("unexpected premises")
+	}
+	return expectConclusion(step.Formula,
+		Fantasy(c.popped.premise, c.popped.last))
+}
+
+func (c *checker) checkRule(d Derivation, n int, step Step) error {
+	rule, ok := LookupRule(step.Rule)
+	if !ok {
+		return fmt.Errorf("unknown rule %q", step.Rule)
+	}
+
+	premises := make([]Formula, len(step.Premises))
+	for i, p := range step.Premises {
+		if p < 1 || p >= n {
+			return fmt.Errorf("premise %d is not an earlier step", p)
+		}
+		if c.scopes[p] != c.current {
+			return fmt.Errorf("premise %d is not in the same fantasy", p)
+		}
+		premises[i] = d.Steps[p-1].Formula
+	}
+
+	if err := rule(c.current.context(), premises, step.Formula); err != nil {
+		return fmt.Errorf("%s: %s", step.Rule, err)
+	}
+	return nil
+}
@@ -0,0 +1,331 @@
+package derivation
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhuiskamp/tnt"
+	"github.com/jeremyhuiskamp/tnt/token"
+)
+
+const sample = `# the start of a proof
+1  ∀a:∀b:(a+Sb)=S(a+b)    (axiom 3)
+2  ∀b:(a+Sb)=S(a+b)       (specification: 1)
+
+3  [                      (push into fantasy)
+4    a=0                  (premise)
+5    [
+6      ~~a=0              (double-tilde: 4)
+7    ]
+8  ]                      # end of fantasy
+9  <a=0⊃a=0>              (fantasy rule)
+`
+
+func mustParse(t *testing.T, src string) tnt.Formula {
+	t.Helper()
+	f, err := tnt.ParseFormula(src)
+	if err != nil {
+		t.Fatalf("error parsing %q: %s", src, err)
+	}
+	return f
+}
+
+func TestParse(t *testing.T) {
+	d, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := tnt.Derivation{Steps: []tnt.Step{
+		{Formula: mustParse(t, "∀a:∀b:(a+Sb)=S(a+b)"), Rule: "axiom 3"},
+		{Formula: mustParse(t, "∀b:(a+Sb)=S(a+b)"), Rule: "specification", Premises: []int{1}},
+		{Rule: tnt.PUSH},
+		{Formula: mustParse(t, "a=0"), Rule: "premise"},
+		{Rule: tnt.PUSH},
+		{Formula: mustParse(t, "~~a=0"), Rule: "double-tilde", Premises: []int{4}},
+		{Rule: tnt.POP},
+		{Rule: tnt.POP},
+		{Formula: mustParse(t, "<a=0⊃a=0>"), Rule: "fantasy rule"},
+	}}
+	if !reflect.DeepEqual(d, expected) {
+		t.Errorf("expected %+v but got %+v", expected, d)
+	}
+}
+
+// TestCheckProof checks a proof from the axioms that 0 is a left
+// identity of addition.
+func TestCheckProof(t *testing.T) {
+	const proof = `
+ 1  ∀a:(a+0)=a                     (axiom 2)
+ 2  (0+0)=0                        (specification: 1)
+ 3  ∀a:∀b:(a+Sb)=S(a+b)            (axiom 3)
+ 4  ∀b:(0+Sb)=S(0+b)               (specification: 3)
+ 5  (0+Sb)=S(0+b)                  (specification: 4)
+ 6  [
+ 7    (0+b)=b                      (premise)
+ 8    S(0+b)=Sb                    (add S: 7)
+ 9    (0+Sb)=S(0+b)                (carry over: 5)
+10    (0+Sb)=Sb                    (transitivity: 9, 8)
+11  ]
+12  <(0+b)=b⊃(0+Sb)=Sb>            (fantasy rule)
+13  ∀b:<(0+b)=b⊃(0+Sb)=Sb>         (generalization: 12)
+14  ∀b:(0+b)=b                     (induction: 2, 13)
+`
+	d, err := Parse(strings.NewReader(proof))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tnt.Check(d); err != nil {
+		t.Error(err)
+	}
+
+	d.Steps[0].Rule = "axiom 1"
+	expected := "step 1: axiom 1: expected ∀a:~Sa=0 but got ∀a:(a+0)=a"
+	if err := tnt.Check(d); err == nil || err.Error() != expected {
+		t.Errorf("expected error %q but got %v", expected, err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	d, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+	if err := Write(&b, d); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `1  ∀a:∀b:(a+Sb)=S(a+b)    (axiom 3)
+2  ∀b:(a+Sb)=S(a+b)       (specification: 1)
+3  [
+4    a=0                  (premise)
+5    [
+6      ~~a=0              (double-tilde: 4)
+7    ]
+8  ]
+9  <a=0⊃a=0>              (fantasy rule)
+`
+	if b.String() != expected {
+		t.Errorf("expected:\n%s\nbut got:\n%s", expected, b.String())
+	}
+
+	reparsed, err := Parse(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(reparsed, d) {
+		t.Errorf("expected written derivation to parse as %+v but got %+v",
+			d, reparsed)
+	}
+}
+
+func TestWriteNumberWidth(t *testing.T) {
+	var d tnt.Derivation
+	for i := 0; i < 10; i++ {
+		d.Add(mustParse(t, "0=0"), "given")
+	}
+
+	var b strings.Builder
+	if err := Write(&b, d); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(b.String(), "\n")
+	if lines[0] != "1   0=0    (given)" || lines[9] != "10  0=0    (given)" {
+		t.Errorf("expected aligned numbers but got:\n%s", b.String())
+	}
+}
+
+func TestWriteInvalidRule(t *testing.T) {
+	for rule, expected := range map[string]string{
+		"":                  "step 2: missing rule",
+		" given":            `step 2: rule " given" has surrounding space`,
+		"given: 1":          `step 2: rule "given: 1" contains ':'`,
+		"given\n3  0=0 (x)": `step 2: rule "given\n3  0=0 (x)" contains '\n'`,
+		"given)":            `step 2: rule "given)" contains ')'`,
+	} {
+		var d tnt.Derivation
+		d.Add(mustParse(t, "0=0"), "given")
+		d.Add(mustParse(t, "0=0"), rule)
+
+		var b strings.Builder
+		err := Write(&b, d)
+		if err == nil || err.Error() != expected {
+			t.Errorf("%q: expected error %q but got %v", rule, expected, err)
+		}
+		if b.Len() != 0 {
+			t.Errorf("%q: expected nothing written but got %q", rule, b.String())
+		}
+	}
+}
+
+func TestParseLongLine(t *testing.T) {
+	src := "1  0=" + strings.Repeat("S", 100000) + "0  (given)\n"
+	d, err := Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Steps) != 1 || d.Steps[0].Formula.String() != src[3:len(src)-10] {
+		t.Errorf("expected one step with a long numeral but got %+v", d.Steps)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for name, test := range map[string]struct {
+		Input   string
+		Pos     token.Position
+		Message string
+	}{
+		"missing number": {
+			Input:   "\n  a=0 (given)",
+			Pos:     token.Position{Offset: 3, Line: 2, Column: 3},
+			Message: "2:3: expected step number",
+		},
+		"wrong number": {
+			Input:   "1 0=0 (given)\n3 0=0 (given)",
+			Pos:     token.Position{Offset: 14, Line: 2, Column: 1},
+			Message: "2:1: expected step 2 but got 3",
+		},
+		"wrong number after CRLF": {
+			Input:   "1 0=0 (given)\r\n3 0=0 (given)",
+			Pos:     token.Position{Offset: 15, Line: 2, Column: 1},
+			Message: "2:1: expected step 2 but got 3",
+		},
+		"no space after number": {
+			Input:   "1[",
+			Pos:     token.Position{Offset: 1, Line: 1, Column: 2},
+			Message: "1:2: expected space after step number",
+		},
+		"missing rule": {
+			Input:   "1  0=0",
+			Pos:     token.Position{Offset: 6, Line: 1, Column: 7},
+			Message: "1:7: expected rule in parentheses",
+		},
+		"missing formula": {
+			Input:   "1  (given)",
+			Pos:     token.Position{Offset: 3, Line: 1, Column: 4},
+			Message: "1:4: expected formula before rule",
+		},
+		"empty rule": {
+			Input:   "1  0=0 ( : 1)",
+			Pos:     token.Position{Offset: 8, Line: 1, Column: 9},
+			Message: "1:9: expected rule name",
+		},
+		"bad premise": {
+			Input:   "1  0=0 (joining: 1, x)",
+			Pos:     token.Position{Offset: 20, Line: 1, Column: 21},
+			Message: `1:21: expected step number but got "x"`,
+		},
+		"wrong bracket rule": {
+			Input:   "1 [ (pop out of fantasy)",
+			Pos:     token.Position{Offset: 4, Line: 1, Column: 5},
+			Message: "1:5: expected (push into fantasy)",
+		},
+		"bad formula": {
+			Input:   "1 0=0 (given)\n2   <0=0∧0=0 (given)",
+			Pos:     token.Position{Offset: 26, Line: 2, Column: 13},
+			Message: "2:13: expected > but got EOF",
+		},
+	} {
+		_, err := Parse(strings.NewReader(test.Input))
+		if err == nil {
+			t.Errorf("%s: expected error", name)
+			continue
+		}
+		if err.Error() != test.Message {
+			t.Errorf("%s: expected message %q but got %q",
+				name, test.Message, err)
+		}
+
+		var pos token.Position
+		var derr *Error
+		var perr *tnt.ParseError
+		switch {
+		case errors.As(err, &derr):
+			pos = derr.Pos
+		case errors.As(err, &perr):
+			pos = perr.Pos
+			if perr.Src != test.Input {
+				t.Errorf("%s: expected source to be whole input but got %q",
+					name, perr.Src)
+			}
+		default:
+			t.Errorf("%s: unexpected error type %T", name, err)
+		}
+		if pos != test.Pos {
+			t.Errorf("%s: expected error at %+v but got %+v",
+				name, test.Pos, pos)
+		}
+	}
+}
+
+func TestParseErrorExcerpt(t *testing.T) {
+	_, err := Parse(strings.NewReader("1  0=0  (given)\n2  S0=_0  (given)\n"))
+	perr, ok := err.(*tnt.ParseError)
+	if !ok {
+		t.Fatalf("expected *tnt.ParseError but got %T: %v", err, err)
+	}
+	expected := "2  S0=_0  (given)\n      ^"
+	if perr.Excerpt() != expected {
+		t.Errorf("expected excerpt:\n%s\nbut got:\n%s", expected, perr.Excerpt())
+	}
+}
+
+func TestParseStep(t *testing.T) {
+	step, err := ParseStep("  (0+Sb)=Sb   (transitivity: 9, 8)  # done")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := tnt.Step{
+		Formula:  mustParse(t, "(0+Sb)=Sb"),
+		Rule:     "transitivity",
+		Premises: []int{9, 8},
+	}
+	if !reflect.DeepEqual(step, expected) {
+		t.Errorf("expected %+v but got %+v", expected, step)
+	}
+
+	for src, expected := range map[string]string{
+		"":                  "1:1: expected step",
+		"a=0":               "1:4: expected rule in parentheses",
+		"a=_  (premise)":    "1:3: expected 0, S, VARIABLE or ( but got ILLEGAL \"_\"",
+		"a=0  (joining: x)": "1:16: expected step number but got \"x\"",
+	} {
+		if _, err := ParseStep(src); err == nil || err.Error() != expected {
+			t.Errorf("%q: expected error %q but got %v", src, expected, err)
+		}
+	}
+}
+
+func TestParsePositions(t *testing.T) {
+	_, positions, err := ParsePositions(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(positions) != 9 {
+		t.Fatalf("expected positions of 9 steps but got %d", len(positions))
+	}
+
+	at := func(line, column int) token.Position {
+		return token.Position{Line: line, Column: column}
+	}
+	second := positions[1]
+	for name, test := range map[string]struct{ got, expected token.Position }{
+		"number":  {second.Number, at(3, 1)},
+		"formula": {second.Formula, at(3, 4)},
+		"rule":    {second.Rule, at(3, 28)},
+		"premise": {second.Premises[0], at(3, 43)},
+	} {
+		got := test.got
+		got.Offset = 0
+		if got != test.expected {
+			t.Errorf("%s: expected %s but got %s", name, test.expected, got)
+		}
+	}
+	if pos := positions[7].Number; pos.Line != 10 {
+		t.Errorf("expected step 8 on line 10 but got %s", pos)
+	}
+}
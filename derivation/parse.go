@@ -0,0 +1,323 @@
+// Package derivation reads and writes TNT derivations laid out as in the
+// book:
+//
+//	1  ∀a:∀b:(a+Sb)=S(a+b)    (axiom 3)
+//	2  ∀b:(a+Sb)=S(a+b)       (specification: 1)
+//	3  [
+//	4    a=0                  (premise)
+//	5  ]
+//
+// Each line holds the number of a step, its formula and, in parentheses,
+// the name of the rule that produced it.  The rule may be followed by a
+// colon and a comma-separated list of the steps it was applied to.
+// Lines holding only [ or ] enter and leave a fantasy, and may also be
+// annotated with a rule.  Indentation, blank lines and anything after a
+// # are ignored.
+package derivation
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jeremyhuiskamp/tnt"
+	"github.com/jeremyhuiskamp/tnt/token"
+)
+
+// Error describes a problem with the layout of a derivation.
+type Error struct {
+	Pos token.Position
+	Err error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Parse reads a derivation.  Problems with the layout are reported as an
+// *Error.  Formulas are parsed with tnt.ParseFormula, and problems with
+// them are reported as a *tnt.ParseError whose Src is the whole input
+// and whose Pos is relative to it.
+//
+// Parse only checks the layout; tnt.Check checks the logic.
+func Parse(r io.Reader) (tnt.Derivation, error) {
+	d, _, err := ParsePositions(r)
+	return d, err
+}
+
+// Positions records where the parts of a step appear in the input.
+type Positions struct {
+	// Number is the position of the step number.
+	Number token.Position
+	// Formula is the position of the formula, if the step has one.
+	Formula token.Position
+	// Rule is the position of the rule, if the step is annotated.
+	Rule token.Position
+	// Premises are the positions of the numbers of the premises.
+	Premises []token.Position
+}
+
+// ParsePositions is like Parse, but also returns the Positions of each
+// step, by step number - 1.  When there is an error, the derivation and
+// Positions read before it are returned.
+func ParsePositions(r io.Reader) (tnt.Derivation, []Positions, error) {
+	var d tnt.Derivation
+	var positions []Positions
+
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return d, positions, err
+	}
+
+	s := bufio.NewScanner(strings.NewReader(string(src)))
+	// The input is already in memory, so a line may be as long as all of
+	// it, such as a step with a large numeral.
+	s.Buffer(nil, len(src)+1)
+	// length is the number of runes in the line scanned, including the
+	// line ending that bufio.ScanLines drops
+	var length int
+	s.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, tok, err := bufio.ScanLines(data, atEOF)
+		length = utf8.RuneCount(data[:advance])
+		return advance, tok, err
+	})
+	line := line{pos: token.Position{Line: 1, Column: 1}}
+	for s.Scan() {
+		line.text = []rune(s.Text())
+		line.positions = &Positions{}
+
+		number, step, ok, err := line.parse()
+		if err != nil {
+			if perr, isParseError := err.(*tnt.ParseError); isParseError {
+				perr.Src = string(src)
+			}
+			return d, positions, err
+		}
+		if ok {
+			if expected := len(d.Steps) + 1; number != expected {
+				return d, positions, &Error{
+					Pos: line.positions.Number,
+					Err: fmt.Errorf("expected step %d but got %d", expected, number),
+				}
+			}
+			d.Steps = append(d.Steps, step)
+			positions = append(positions, *line.positions)
+		}
+
+		line.pos.Offset += length
+		line.pos.Line++
+	}
+	return d, positions, s.Err()
+}
+
+// ParseStep parses a single step laid out as in a derivation, but
+// without its number, such as "(0+Sb)=Sb  (transitivity: 9, 8)".
+// Errors are reported as by Parse, with src as the whole input.
+func ParseStep(src string) (tnt.Step, error) {
+	l := line{
+		text:      []rune(src),
+		pos:       token.Position{Line: 1, Column: 1},
+		positions: &Positions{},
+	}
+	i, end := l.trim()
+	if i >= end {
+		return tnt.Step{}, l.errorf(i, "expected step")
+	}
+	step, err := l.parseStep(i, end)
+	if perr, ok := err.(*tnt.ParseError); ok {
+		perr.Src = src
+	}
+	return step, err
+}
+
+// line is a line of input being parsed.
+type line struct {
+	text []rune
+	// pos is the position of the start of the line
+	pos token.Position
+	// positions records the parts of the step on the line
+	positions *Positions
+}
+
+// at returns the position of the rune at index i of the line.
+func (l line) at(i int) token.Position {
+	return token.Position{
+		Offset: l.pos.Offset + i,
+		Line:   l.pos.Line,
+		Column: i + 1,
+	}
+}
+
+func (l line) errorf(i int, format string, args ...interface{}) error {
+	return &Error{Pos: l.at(i), Err: fmt.Errorf(format, args...)}
+}
+
+// skipSpace returns the index of the first non-space rune at or after
+// i, or end if there is none before end.
+func (l line) skipSpace(i, end int) int {
+	for i < end && unicode.IsSpace(l.text[i]) {
+		i++
+	}
+	return i
+}
+
+// trim returns the range of the line before any comment, without
+// surrounding space.
+func (l line) trim() (i, end int) {
+	end = len(l.text)
+	for i, r := range l.text {
+		if r == '#' {
+			end = i
+			break
+		}
+	}
+	for end > 0 && unicode.IsSpace(l.text[end-1]) {
+		end--
+	}
+	return l.skipSpace(0, end), end
+}
+
+// parse parses the line.  If the line holds a step, ok is true.
+func (l line) parse() (number int, step tnt.Step, ok bool, err error) {
+	i, end := l.trim()
+	if i >= end {
+		return 0, step, false, nil
+	}
+
+	start := i
+	for i < end && unicode.IsDigit(l.text[i]) {
+		i++
+	}
+	if i == start {
+		return 0, step, false, l.errorf(i, "expected step number")
+	}
+	l.positions.Number = l.at(start)
+	number, err = strconv.Atoi(string(l.text[start:i]))
+	if err != nil {
+		return 0, step, false, l.errorf(start, "invalid step number: %s", err)
+	}
+	if i >= end || !unicode.IsSpace(l.text[i]) {
+		return 0, step, false, l.errorf(i, "expected space after step number")
+	}
+	i = l.skipSpace(i, end)
+
+	step, err = l.parseStep(i, end)
+	return number, step, err == nil, err
+}
+
+// parseStep parses the part of a line after the step number, in the
+// range [i, end) of the line.
+func (l line) parseStep(i, end int) (tnt.Step, error) {
+	switch l.text[i] {
+	case '[':
+		return l.parseBracket(i, end, tnt.PUSH)
+	case ']':
+		return l.parseBracket(i, end, tnt.POP)
+	}
+	return l.parseFormulaStep(i, end)
+}
+
+// parseBracket parses a line entering or leaving a fantasy, starting at
+// the bracket at index i.
+func (l line) parseBracket(i, end int, rule string) (tnt.Step, error) {
+	step := tnt.Step{Rule: rule}
+	i = l.skipSpace(i+1, end)
+	if i == end {
+		return step, nil
+	}
+
+	annotated, err := l.parseAnnotation(i, end)
+	if err != nil {
+		return step, err
+	}
+	if !strings.EqualFold(annotated.Rule, rule) || len(annotated.Premises) != 0 {
+		return step, l.errorf(i, "expected (%s)", rule)
+	}
+	return step, nil
+}
+
+// parseFormulaStep parses a formula followed by an annotation, in the
+// range [i, end) of the line.
+func (l line) parseFormulaStep(i, end int) (tnt.Step, error) {
+	if l.text[end-1] != ')' {
+		return tnt.Step{}, l.errorf(end, "expected rule in parentheses")
+	}
+
+	// find the parenthesis opening the annotation
+	open, depth := end-1, 0
+	for ; open >= i; open-- {
+		if l.text[open] == ')' {
+			depth++
+		} else if l.text[open] == '(' {
+			depth--
+			if depth == 0 {
+				break
+			}
+		}
+	}
+	if open < i {
+		return tnt.Step{}, l.errorf(end-1, "unbalanced parentheses")
+	}
+
+	step, err := l.parseAnnotation(open, end)
+	if err != nil {
+		return step, err
+	}
+
+	src := strings.TrimRightFunc(string(l.text[i:open]), unicode.IsSpace)
+	if src == "" {
+		return step, l.errorf(i, "expected formula before rule")
+	}
+	l.positions.Formula = l.at(i)
+	step.Formula, err = tnt.ParseFormula(src)
+	if perr, ok := err.(*tnt.ParseError); ok {
+		// The formula is on a single line, so only the line and column
+		// need adjusting.
+		perr.Pos = l.at(i + perr.Pos.Offset)
+		return step, perr
+	}
+	return step, err
+}
+
+// parseAnnotation parses a rule and its premises, from the opening
+// parenthesis at index i up to end.
+func (l line) parseAnnotation(i, end int) (tnt.Step, error) {
+	var step tnt.Step
+	if l.text[i] != '(' || l.text[end-1] != ')' {
+		return step, l.errorf(i, "expected rule in parentheses")
+	}
+
+	inner := string(l.text[i+1 : end-1])
+	rule, premises, hasPremises := strings.Cut(inner, ":")
+	step.Rule = strings.TrimSpace(rule)
+	if step.Rule == "" {
+		return step, l.errorf(i+1, "expected rule name")
+	}
+	l.positions.Rule = l.at(l.skipSpace(i+1, end))
+	if !hasPremises {
+		return step, nil
+	}
+
+	// index of the rune after the colon
+	j := i + 1 + len([]rune(rule)) + 1
+	for _, premise := range strings.Split(premises, ",") {
+		trimmed := strings.TrimSpace(premise)
+		k := j + len([]rune(premise)) - len([]rune(strings.TrimLeftFunc(premise, unicode.IsSpace)))
+		n, err := strconv.Atoi(trimmed)
+		if err != nil || n < 1 {
+			return step, l.errorf(k, "expected step number but got %q", trimmed)
+		}
+		step.Premises = append(step.Premises, n)
+		l.positions.Premises = append(l.positions.Premises, l.at(k))
+		j += len([]rune(premise)) + 1
+	}
+	return step, nil
+}
@@ -0,0 +1,92 @@
+package derivation
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jeremyhuiskamp/tnt"
+)
+
+// indent is the indentation of each level of fantasy.
+const indent = "  "
+
+// Write writes d in the layout read by Parse.  The contents of each
+// fantasy are indented, and rules are aligned in a column.  It returns
+// an error, having written nothing, if a rule could not be read back.
+func Write(w io.Writer, d tnt.Derivation) error {
+	for i, step := range d.Steps {
+		if err := checkRuleName(step.Rule); err != nil {
+			return fmt.Errorf("step %d: %s", i+1, err)
+		}
+	}
+
+	numberWidth := len(strconv.Itoa(len(d.Steps)))
+
+	lines := make([]string, len(d.Steps))
+	width, depth := 0, 0
+	for i, step := range d.Steps {
+		var text string
+		switch strings.ToLower(step.Rule) {
+		case tnt.PUSH:
+			text = strings.Repeat(indent, depth) + "["
+			depth++
+		case tnt.POP:
+			if depth > 0 {
+				depth--
+			}
+			text = strings.Repeat(indent, depth) + "]"
+		default:
+			text = strings.Repeat(indent, depth)
+			if step.Formula != nil {
+				text += step.Formula.String()
+			}
+		}
+		lines[i] = text
+		if n := utf8.RuneCountInString(text); n > width {
+			width = n
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	for i, step := range d.Steps {
+		fmt.Fprintf(bw, "%-*d  %s", numberWidth, i+1, lines[i])
+		if rule := strings.ToLower(step.Rule); rule != tnt.PUSH && rule != tnt.POP {
+			padding := width - utf8.RuneCountInString(lines[i]) + 4
+			fmt.Fprintf(bw, "%s(%s)", strings.Repeat(" ", padding), annotation(step))
+		}
+		bw.WriteString("\n")
+	}
+	return bw.Flush()
+}
+
+// annotation returns the rule and premises of a step as they appear
+// inside parentheses.
+func annotation(step tnt.Step) string {
+	if len(step.Premises) == 0 {
+		return step.Rule
+	}
+	premises := make([]string, len(step.Premises))
+	for i, p := range step.Premises {
+		premises[i] = strconv.Itoa(p)
+	}
+	return step.Rule + ": " + strings.Join(premises, ", ")
+}
+
+// checkRuleName reports whether Parse would read rule back from an
+// annotation unchanged.
+func checkRuleName(rule string) error {
+	if rule == "" {
+		return fmt.Errorf("missing rule")
+	}
+	if strings.TrimSpace(rule) != rule {
+		return fmt.Errorf("rule %q has surrounding space", rule)
+	}
+	if i := strings.IndexAny(rule, ":#()\r\n"); i >= 0 {
+		return fmt.Errorf("rule %q contains %q", rule, rule[i])
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package tnt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func mustParse(t *testing.T, src string) Formula {
+	t.Helper()
+	f, err := ParseFormula(src)
+	if err != nil {
+		t.Fatalf("error parsing %q: %s", src, err)
+	}
+	return f
+}
+
+// registerRule registers a Rule for the duration of a test.
+func registerRule(t *testing.T, name string, rule Rule) {
+	t.Helper()
+	if _, ok := LookupRule(name); ok {
+		t.Fatalf("rule %q is already registered", name)
+	}
+	RegisterRule(name, rule)
+	t.Cleanup(func() {
+		rulesMu.Lock()
+		defer rulesMu.Unlock()
+		delete(rules, strings.ToLower(name))
+	})
+}
+
+// registerTestRules registers "test given", which accepts any formula
+// without premises, and "test negation", which negates its single
+// premise.
+func registerTestRules(t *testing.T) {
+	t.Helper()
+	registerRule(t, "test given", func(_ Context, premises []Formula, conclusion Formula) error {
+		if len(premises) != 0 {
+			return fmt.Errorf("expected no premises")
+		}
+		return nil
+	})
+	registerRule(t, "Test Negation", func(_ Context, premises []Formula, conclusion Formula) error {
+		if len(premises) != 1 {
+			return fmt.Errorf("expected 1 premise but got %d", len(premises))
+		}
+		if !Equal(conclusion, Negation{premises[0]}) {
+			return fmt.Errorf("%s is not the negation of %s",
+				conclusion, premises[0])
+		}
+		return nil
+	})
+}
+
+func TestCheck(t *testing.T) {
+	registerTestRules(t)
+	var d Derivation
+	one := d.Add(mustParse(t, "a=0"), "test given")
+	two := d.Add(mustParse(t, "~a=0"), "TEST NEGATION", one)
+	d.Add(mustParse(t, "~~a=0"), "test negation", two)
+
+	if err := Check(d); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if step, ok := d.Step(2); !ok || step.Formula.String() != "~a=0" {
+		t.Errorf("expected step 2 to be ~a=0 but got %+v", step)
+	}
+	if _, ok := d.Step(4); ok {
+		t.Error("expected no step 4")
+	}
+}
+
+func TestCheckInvalid(t *testing.T) {
+	registerTestRules(t)
+	for name, test := range map[string]struct {
+		Steps []Step
+		Step  int
+		Error string
+	}{
+		"unknown rule": {
+			Steps: []Step{
+				{mustParse(t, "0=0"), "test given", nil},
+				{mustParse(t, "0=0"), "wishful thinking", []int{1}},
+			},
+			Step:  2,
+			Error: `step 2: unknown rule "wishful thinking"`,
+		},
+		"missing formula": {
+			Steps: []Step{{nil, "test given", nil}},
+			Step:  1,
+			Error: "step 1: missing formula",
+		},
+		"not well formed": {
+			Steps: []Step{{mustParse(t, "Aa:0=0"), "test given", nil}},
+			Step:  1,
+			Error: "step 1: ∀a:0=0 is not well formed: /: a does not occur; " +
+				"a variable may only be quantified in a formula in which it is free",
+		},
+		"forward reference": {
+			Steps: []Step{
+				{mustParse(t, "~0=0"), "test negation", []int{2}},
+				{mustParse(t, "0=0"), "test given", nil},
+			},
+			Step:  1,
+			Error: "step 1: premise 2 is not an earlier step",
+		},
+		"self reference": {
+			Steps: []Step{
+				{mustParse(t, "0=0"), "test given", nil},
+				{mustParse(t, "~0=0"), "test negation", []int{2}},
+			},
+			Step:  2,
+			Error: "step 2: premise 2 is not an earlier step",
+		},
+		"rule fails": {
+			Steps: []Step{
+				{mustParse(t, "0=0"), "test given", nil},
+				{mustParse(t, "~0=0"), "test negation", []int{1}},
+				{mustParse(t, "~0=0"), "test negation", []int{2}},
+			},
+			Step:  3,
+			Error: "step 3: test negation: ~0=0 is not the negation of ~0=0",
+		},
+	} {
+		err := Check(Derivation{Steps: test.Steps})
+		if err == nil {
+			t.Errorf("%s: expected error", name)
+			continue
+		}
+		var stepErr *StepError
+		if !errors.As(err, &stepErr) {
+			t.Errorf("%s: expected *StepError but got %T", name, err)
+		} else if stepErr.Step != test.Step {
+			t.Errorf("%s: expected error at step %d but got %d",
+				name, test.Step, stepErr.Step)
+		}
+		if err.Error() != test.Error {
+			t.Errorf("%s: expected error %q but got %q", name, test.Error, err)
+		}
+	}
+}
+
+func TestRuleNames(t *testing.T) {
+	contains := func(name string) bool {
+		for _, n := range RuleNames() {
+			if n == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	names := RuleNames()
+	for i, name := range names {
+		if i > 0 && strings.ToLower(names[i-1]) > strings.ToLower(name) {
+			t.Errorf("%s is out of order after %s", name, names[i-1])
+		}
+	}
+	if !contains("De Morgan") {
+		t.Errorf("expected De Morgan in %v", names)
+	}
+
+	t.Run("registered", func(t *testing.T) {
+		registerTestRules(t)
+		if !contains("Test Negation") {
+			t.Errorf("expected Test Negation in %v", RuleNames())
+		}
+	})
+	if contains("Test Negation") {
+		t.Errorf("expected Test Negation to be removed from %v", RuleNames())
+	}
+}
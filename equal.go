@@ -0,0 +1,248 @@
+package tnt
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+)
+
+// EqualTerms returns true if a and b are the same Term.  Successors are
+// compared by the number of S's they denote, regardless of how they are
+// nested, so Successor{1, Successor{1, a}}, Successor{2, a} are equal,
+// as are Successor{1, Numeral(0)} and Numeral(1).
+func EqualTerms(a, b Term) bool {
+	return equalTerms(a, b, renaming{}.sameVariable)
+}
+
+// Equal returns true if a and b are the same Formula, with Terms compared
+// as by EqualTerms.
+func Equal(a, b Formula) bool {
+	return equalFormulas(a, b, renaming{})
+}
+
+// AlphaEquivalent returns true if a and b are the same Formula up to
+// renaming of quantified Variables, eg ∀a:∃b:a=b and ∀c:∃d:c=d.
+// Free Variables must have the same names in both.
+func AlphaEquivalent(a, b Formula) bool {
+	return equalFormulas(a, b, renaming{
+		left:  map[Variable]int{},
+		right: map[Variable]int{},
+	})
+}
+
+// Hash returns a hash of f that is the same for all Formulas that are
+// Equal.  Formulas that are only AlphaEquivalent usually hash
+// differently; use AlphaHash for them.  It is stable across processes.
+func Hash(f Formula) uint64 {
+	h := newHasher(nil)
+	h.formula(f)
+	return h.Sum64()
+}
+
+// AlphaHash returns a hash of f that is the same for all Formulas that
+// are AlphaEquivalent.  It is stable across processes.
+func AlphaHash(f Formula) uint64 {
+	h := newHasher(map[Variable]int{})
+	h.formula(f)
+	return h.Sum64()
+}
+
+// HashTerm returns a hash of t that is the same for all Terms that are
+// EqualTerms.  It is stable across processes.
+func HashTerm(t Term) uint64 {
+	h := newHasher(nil)
+	h.term(t)
+	return h.Sum64()
+}
+
+// hasher hashes the structure of Terms and Formulas.  If bound is not
+// nil, quantified Variables are hashed by the depth of their
+// Quantification rather than by name, as AlphaEquivalent compares them.
+type hasher struct {
+	hash.Hash64
+	bound map[Variable]int
+	depth int
+}
+
+func newHasher(bound map[Variable]int) *hasher {
+	return &hasher{Hash64: fnv.New64a(), bound: bound}
+}
+
+func (h *hasher) int(n int) {
+	var buf [binary.MaxVarintLen64]byte
+	h.Write(buf[:binary.PutVarint(buf[:], int64(n))])
+}
+
+func (h *hasher) name(v Variable) {
+	h.int(len(v))
+	h.Write([]byte(v))
+}
+
+func (h *hasher) term(t Term) {
+	// Fold nested Successors, as EqualTerms does.
+	quantity := 0
+	for {
+		s, ok := t.(Successor)
+		if !ok {
+			break
+		}
+		quantity += s.Quantity
+		t = s.Term
+	}
+	if n, ok := t.(Numeral); ok {
+		h.Write([]byte{'0'})
+		h.int(int(n) + quantity)
+		return
+	}
+	if quantity > 0 {
+		h.Write([]byte{'S'})
+		h.int(quantity)
+	}
+
+	switch t := t.(type) {
+	case Variable:
+		if depth, ok := h.bound[t]; ok {
+			h.Write([]byte{'#'})
+			h.int(depth)
+		} else {
+			h.Write([]byte{'v'})
+			h.name(t)
+		}
+	case CompoundTerm:
+		h.Write([]byte{'('})
+		h.int(int(t.Kind))
+		h.term(t.Left)
+		h.term(t.Right)
+	}
+}
+
+func (h *hasher) formula(f Formula) {
+	switch f := f.(type) {
+	case Atom:
+		h.Write([]byte{'='})
+		h.term(f.Left)
+		h.term(f.Right)
+	case Negation:
+		h.Write([]byte{'~'})
+		h.formula(f.Formula)
+	case Compound:
+		h.Write([]byte{'<'})
+		h.int(int(f.Kind))
+		h.formula(f.Left)
+		h.formula(f.Right)
+	case Quantification:
+		h.Write([]byte{':'})
+		h.int(int(f.Kind))
+		if h.bound == nil {
+			h.name(f.Variable)
+			h.formula(f.Formula)
+			return
+		}
+		previous, shadowed := h.bound[f.Variable]
+		h.bound[f.Variable] = h.depth
+		h.depth++
+		h.formula(f.Formula)
+		h.depth--
+		if shadowed {
+			h.bound[f.Variable] = previous
+		} else {
+			delete(h.bound, f.Variable)
+		}
+	}
+}
+
+// normalize folds nested Successors at the top of t.
+func normalize(t Term) Term {
+	if s, ok := t.(Successor); ok {
+		return successor(s.Quantity, s.Term)
+	}
+	return t
+}
+
+func equalTerms(a, b Term, sameVariable func(a, b Variable) bool) bool {
+	a, b = normalize(a), normalize(b)
+	switch a := a.(type) {
+	case Numeral:
+		b, ok := b.(Numeral)
+		return ok && a == b
+	case Variable:
+		b, ok := b.(Variable)
+		return ok && sameVariable(a, b)
+	case Successor:
+		b, ok := b.(Successor)
+		return ok && a.Quantity == b.Quantity &&
+			equalTerms(a.Term, b.Term, sameVariable)
+	case CompoundTerm:
+		b, ok := b.(CompoundTerm)
+		return ok && a.Kind == b.Kind &&
+			equalTerms(a.Left, b.Left, sameVariable) &&
+			equalTerms(a.Right, b.Right, sameVariable)
+	}
+	return false
+}
+
+// renaming pairs up the quantified Variables of two Formulas being
+// compared.  Each quantified Variable maps to the depth of its
+// Quantification.  If the maps are nil, Variables are compared by name.
+type renaming struct {
+	left, right map[Variable]int
+	depth       int
+}
+
+func (r renaming) bind(a, b Variable) renaming {
+	bound := renaming{
+		left:  make(map[Variable]int, len(r.left)+1),
+		right: make(map[Variable]int, len(r.right)+1),
+		depth: r.depth + 1,
+	}
+	for k, v := range r.left {
+		bound.left[k] = v
+	}
+	for k, v := range r.right {
+		bound.right[k] = v
+	}
+	bound.left[a] = r.depth
+	bound.right[b] = r.depth
+	return bound
+}
+
+func (r renaming) sameVariable(a, b Variable) bool {
+	if r.left == nil {
+		return a == b
+	}
+	da, aBound := r.left[a]
+	db, bBound := r.right[b]
+	if aBound || bBound {
+		return aBound && bBound && da == db
+	}
+	return a == b
+}
+
+func equalFormulas(a, b Formula, r renaming) bool {
+	switch a := a.(type) {
+	case Atom:
+		b, ok := b.(Atom)
+		return ok &&
+			equalTerms(a.Left, b.Left, r.sameVariable) &&
+			equalTerms(a.Right, b.Right, r.sameVariable)
+	case Negation:
+		b, ok := b.(Negation)
+		return ok && equalFormulas(a.Formula, b.Formula, r)
+	case Compound:
+		b, ok := b.(Compound)
+		return ok && a.Kind == b.Kind &&
+			equalFormulas(a.Left, b.Left, r) &&
+			equalFormulas(a.Right, b.Right, r)
+	case Quantification:
+		b, ok := b.(Quantification)
+		if !ok || a.Kind != b.Kind {
+			return false
+		}
+		if r.left == nil {
+			return a.Variable == b.Variable &&
+				equalFormulas(a.Formula, b.Formula, r)
+		}
+		return equalFormulas(a.Formula, b.Formula, r.bind(a.Variable, b.Variable))
+	}
+	return false
+}
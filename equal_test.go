@@ -0,0 +1,109 @@
+package tnt
+
+import "testing"
+
+func TestEqualTerms(t *testing.T) {
+	a := Variable("a")
+	for _, test := range []struct {
+		A, B  Term
+		Equal bool
+	}{
+		{Numeral(2), Numeral(2), true},
+		{Numeral(2), Numeral(3), false},
+		{Numeral(0), a, false},
+		{a, Variable("b"), false},
+		{Successor{1, Successor{1, a}}, Successor{2, a}, true},
+		{Successor{1, Numeral(0)}, Numeral(1), true},
+		{Successor{0, a}, a, true},
+		{Successor{2, a}, Successor{1, a}, false},
+		{CompoundTerm{PLUS, a, Successor{1, Numeral(1)}},
+			CompoundTerm{PLUS, a, Numeral(2)}, true},
+		{CompoundTerm{PLUS, a, Numeral(2)},
+			CompoundTerm{MULTIPLY, a, Numeral(2)}, false},
+	} {
+		if got := EqualTerms(test.A, test.B); got != test.Equal {
+			t.Errorf("expected EqualTerms(%#v, %#v) to be %t",
+				test.A, test.B, test.Equal)
+		}
+		if test.Equal && HashTerm(test.A) != HashTerm(test.B) {
+			t.Errorf("expected %#v and %#v to hash the same",
+				test.A, test.B)
+		}
+	}
+}
+
+func TestEqual(t *testing.T) {
+	for _, test := range []struct {
+		A, B              string
+		Equal, Equivalent bool
+	}{
+		{"0=0", "0=0", true, true},
+		{"0=0", "~0=0", false, false},
+		{"<a=0∧b=0>", "<a=0∨b=0>", false, false},
+		{"∀a:a=b", "∀a:a=b", true, true},
+		{"∀a:a=b", "∃a:a=b", false, false},
+		{"∀a:a=b", "∀c:c=b", false, true},
+		{"∀a:a=b", "∀b:b=b", false, false},
+		{"∀a:a=b", "∀a:a=c", false, false},
+		{"∀a:∃b:(a+b)=Sc", "∀b:∃a:(b+a)=Sc", false, true},
+		{"∀a:∃b:(a+b)=Sc", "∀b:∃a:(a+b)=Sc", false, false},
+		{"<∀a:a=0∧∀b:b=0>", "<∀c:c=0∧∀c:c=0>", false, true},
+		{"∀a:∀a:a=0", "∀a:∀b:b=0", false, true},
+		{"∀a:∀a:a=0", "∀a:∀b:a=0", false, false},
+		{"∀a:∀b:a=0", "∀b:∀b:b=0", false, false},
+	} {
+		a, err := ParseFormula(test.A)
+		if err != nil {
+			t.Fatalf("error parsing %q: %s", test.A, err)
+		}
+		b, err := ParseFormula(test.B)
+		if err != nil {
+			t.Fatalf("error parsing %q: %s", test.B, err)
+		}
+
+		for _, pair := range [][2]Formula{{a, b}, {b, a}} {
+			if got := Equal(pair[0], pair[1]); got != test.Equal {
+				t.Errorf("expected Equal(%s, %s) to be %t",
+					pair[0], pair[1], test.Equal)
+			}
+			if got := AlphaEquivalent(pair[0], pair[1]); got != test.Equivalent {
+				t.Errorf("expected AlphaEquivalent(%s, %s) to be %t",
+					pair[0], pair[1], test.Equivalent)
+			}
+		}
+		if test.Equal != (Hash(a) == Hash(b)) {
+			t.Errorf("expected hashes of %s and %s to be equal: %t",
+				a, b, test.Equal)
+		}
+		if test.Equivalent != (AlphaHash(a) == AlphaHash(b)) {
+			t.Errorf("expected alpha hashes of %s and %s to be equal: %t",
+				a, b, test.Equivalent)
+		}
+	}
+}
+
+func TestEqualNormalizesSuccessors(t *testing.T) {
+	parsed, err := ParseFormula("SSa=SS0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	built := Atom{
+		Left:  Successor{1, Successor{1, Variable("a")}},
+		Right: Successor{2, Numeral(0)},
+	}
+	if !Equal(parsed, built) {
+		t.Errorf("expected %#v to equal %#v", parsed, built)
+	}
+	if Hash(parsed) != Hash(built) {
+		t.Errorf("expected %#v and %#v to hash the same", parsed, built)
+	}
+}
+
+func TestHashLargeNumeral(t *testing.T) {
+	// Hashing must not print the Numeral, which takes an S per unit.
+	a := Atom{Numeral(1 << 40), Successor{1, Numeral(1<<40 - 1)}}
+	b := Atom{Successor{1 << 40, Numeral(0)}, Numeral(1 << 40)}
+	if Hash(a) != Hash(b) || Hash(a) == Hash(Atom{Numeral(1 << 40), Numeral(0)}) {
+		t.Errorf("expected %#v and %#v to hash the same", a, b)
+	}
+}
@@ -0,0 +1,140 @@
+package tnt
+
+import "fmt"
+
+// This file implements the rules of Chapter 8 about equality and
+// successorship.  Successors are handled in the form that ParseFormula
+// produces, so SS0 and S(S0) are treated alike.
+
+func init() {
+	RegisterRule("symmetry", symmetry)
+	RegisterRule("transitivity", transitivity)
+	RegisterRule("add S", addS)
+	RegisterRule("drop S", dropS)
+}
+
+// expectAtom returns f as an Atom, or an error if it is not one.
+func expectAtom(f Formula) (Atom, error) {
+	a, ok := f.(Atom)
+	if !ok {
+		return Atom{}, fmt.Errorf("%s is not of the form r=s", f)
+	}
+	return a, nil
+}
+
+// Symmetrize applies the rule of symmetry: if r=s is a theorem, then so
+// is s=r.
+func Symmetrize(f Formula) (Formula, error) {
+	a, err := expectAtom(f)
+	if err != nil {
+		return nil, err
+	}
+	return Atom{Left: a.Right, Right: a.Left}, nil
+}
+
+func symmetry(_ Context, premises []Formula, conclusion Formula) error {
+	if err := expectPremises(premises, 1); err != nil {
+		return err
+	}
+	symmetric, err := Symmetrize(premises[0])
+	if err != nil {
+		return err
+	}
+	return expectConclusion(conclusion, symmetric)
+}
+
+// Transit applies the rule of transitivity: if r=s and s=t are
+// theorems, then so is r=t.
+func Transit(x, y Formula) (Formula, error) {
+	left, err := expectAtom(x)
+	if err != nil {
+		return nil, err
+	}
+	right, err := expectAtom(y)
+	if err != nil {
+		return nil, err
+	}
+	if !EqualTerms(left.Right, right.Left) {
+		return nil, fmt.Errorf("%s and %s do not share the term %s",
+			x, y, left.Right)
+	}
+	return Atom{Left: left.Left, Right: right.Right}, nil
+}
+
+func transitivity(_ Context, premises []Formula, conclusion Formula) error {
+	if err := expectPremises(premises, 2); err != nil {
+		return err
+	}
+	r, err := Transit(premises[0], premises[1])
+	if err != nil {
+		// The premises may be given in the other order.
+		var otherErr error
+		if r, otherErr = Transit(premises[1], premises[0]); otherErr != nil {
+			return err
+		}
+	}
+	return expectConclusion(conclusion, r)
+}
+
+// AddS applies the rule of add S: if r=t is a theorem, then so is
+// Sr=St.
+func AddS(f Formula) (Formula, error) {
+	a, err := expectAtom(f)
+	if err != nil {
+		return nil, err
+	}
+	return Atom{
+		Left:  successor(1, a.Left),
+		Right: successor(1, a.Right),
+	}, nil
+}
+
+func addS(_ Context, premises []Formula, conclusion Formula) error {
+	if err := expectPremises(premises, 1); err != nil {
+		return err
+	}
+	added, err := AddS(premises[0])
+	if err != nil {
+		return err
+	}
+	return expectConclusion(conclusion, added)
+}
+
+// DropS applies the rule of drop S: if Sr=St is a theorem, then so is
+// r=t.
+func DropS(f Formula) (Formula, error) {
+	a, err := expectAtom(f)
+	if err != nil {
+		return nil, err
+	}
+	left, leftOK := predecessor(a.Left)
+	right, rightOK := predecessor(a.Right)
+	if !leftOK || !rightOK {
+		return nil, fmt.Errorf("%s is not of the form Sr=St", f)
+	}
+	return Atom{Left: left, Right: right}, nil
+}
+
+// predecessor returns t without its outermost S, if it has one.
+func predecessor(t Term) (Term, bool) {
+	switch t := normalize(t).(type) {
+	case Numeral:
+		if t > 0 {
+			return t - 1, true
+		}
+	case Successor:
+		return successor(t.Quantity-1, t.Term), true
+	}
+	return nil, false
+}
+
+func dropS(_ Context, premises []Formula, conclusion Formula) error {
+	if err := expectPremises(premises, 1); err != nil {
+		return err
+	}
+	dropped, err := DropS(premises[0])
+	if err != nil {
+		return err
+	}
+	return expectConclusion(conclusion, dropped)
+}
@@ -0,0 +1,56 @@
+package tnt
+
+import "testing"
+
+func TestSymmetry(t *testing.T) {
+	checkRule(t, "symmetry", []string{"(a+0)=a"}, "a=(a+0)", "")
+	checkRule(t, "symmetry", []string{"(a+0)=a"}, "(a+0)=a",
+		"expected a=(a+0) but got (a+0)=a")
+	checkRule(t, "symmetry", []string{"~a=0"}, "~0=a",
+		"~a=0 is not of the form r=s")
+}
+
+func TestTransitivity(t *testing.T) {
+	checkRule(t, "transitivity", []string{"a=Sb", "Sb=(c+0)"}, "a=(c+0)", "")
+	checkRule(t, "transitivity", []string{"Sb=(c+0)", "a=Sb"}, "a=(c+0)", "")
+	checkRule(t, "transitivity", []string{"a=S0", "S0=SS0"}, "a=SS0", "")
+	checkRule(t, "transitivity", []string{"a=Sb", "b=c"}, "a=c",
+		"a=Sb and b=c do not share the term Sb")
+	checkRule(t, "transitivity", []string{"a=b", "b=c"}, "c=a",
+		"expected a=c but got c=a")
+}
+
+func TestAddS(t *testing.T) {
+	added, err := AddS(mustParse(t, "(a+S0)=Sb"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (Atom{
+		Left:  Successor{1, CompoundTerm{PLUS, Variable("a"), Numeral(1)}},
+		Right: Successor{2, Variable("b")},
+	}); added != expected {
+		t.Errorf("expected %#v but got %#v", expected, added)
+	}
+
+	checkRule(t, "add S", []string{"0=a"}, "S0=Sa", "")
+	checkRule(t, "add S", []string{"S0=Sa"}, "SS0=SSa", "")
+	checkRule(t, "add S", []string{"0=a"}, "SS0=SSa",
+		"expected S0=Sa but got SS0=SSa")
+}
+
+func TestDropS(t *testing.T) {
+	dropped, err := DropS(mustParse(t, "S0=Sa"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (Atom{Left: Numeral(0), Right: Variable("a")}); dropped != expected {
+		t.Errorf("expected %#v but got %#v", expected, dropped)
+	}
+
+	checkRule(t, "drop S", []string{"SS0=SSa"}, "S0=Sa", "")
+	checkRule(t, "drop S", []string{"S(a+b)=SSc"}, "(a+b)=Sc", "")
+	checkRule(t, "drop S", []string{"S0=a"}, "0=a",
+		"S0=a is not of the form Sr=St")
+	checkRule(t, "drop S", []string{"0=S0"}, "0=0",
+		"0=S0 is not of the form Sr=St")
+}
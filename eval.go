@@ -0,0 +1,157 @@
+package tnt
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+)
+
+// Eval returns the natural number denoted by t, with the Variables of t
+// taking their values from env.  An error is returned if t contains a
+// Variable that is not in env.
+func Eval(t Term, env map[Variable]*big.Int) (*big.Int, error) {
+	switch t := t.(type) {
+	case Numeral:
+		return big.NewInt(int64(t)), nil
+	case Variable:
+		value, ok := env[t]
+		if !ok || value == nil {
+			return nil, fmt.Errorf("variable %s is unbound", t)
+		}
+		return new(big.Int).Set(value), nil
+	case Successor:
+		value, err := Eval(t.Term, env)
+		if err != nil {
+			return nil, err
+		}
+		return value.Add(value, big.NewInt(int64(t.Quantity))), nil
+	case CompoundTerm:
+		left, err := Eval(t.Left, env)
+		if err != nil {
+			return nil, err
+		}
+		right, err := Eval(t.Right, env)
+		if err != nil {
+			return nil, err
+		}
+		switch t.Kind {
+		case PLUS:
+			return left.Add(left, right), nil
+		case MULTIPLY:
+			return left.Mul(left, right), nil
+		}
+		return nil, fmt.Errorf("unknown operation %s", t.Kind)
+	}
+	return nil, fmt.Errorf("unknown term type %T", t)
+}
+
+// EvalBounded returns whether f is true when its free Variables take
+// their values from env, and its Quantifications range only over the
+// numbers 0 to bound inclusive.  This only approximates the truth of f,
+// but is useful for finding counterexamples to a candidate theorem.
+//
+// The returned assignment gives values of quantified Variables that
+// decided the result: a witness for a true ∃ or a counterexample to a
+// false ∀.  It is empty if no Quantification decided the result, for
+// example if a ∀ is true for every number.
+//
+// An error is returned if f has a free Variable that is not in env.
+func EvalBounded(f Formula, env map[Variable]*big.Int, bound uint64) (bool, map[Variable]*big.Int, error) {
+	return EvalBoundedContext(context.Background(), f, env, bound)
+}
+
+// EvalBoundedContext is like EvalBounded, but stops with the error of ctx
+// once ctx is done.  Evaluation takes time exponential in the number of
+// nested Quantifications, so this bounds the time it may take.
+func EvalBoundedContext(ctx context.Context, f Formula, env map[Variable]*big.Int, bound uint64) (bool, map[Variable]*big.Int, error) {
+	// Check every free Variable up front, since evaluation may not reach
+	// all of them.
+	for _, v := range f.FreeVariables().sorted() {
+		if _, ok := env[v]; !ok {
+			return false, nil, fmt.Errorf("variable %s is unbound", v)
+		}
+	}
+	scope := make(map[Variable]*big.Int, len(env))
+	for v, value := range env {
+		scope[v] = value
+	}
+	return evalBounded(ctx, f, scope, bound)
+}
+
+func evalBounded(ctx context.Context, f Formula, env map[Variable]*big.Int, bound uint64) (bool, map[Variable]*big.Int, error) {
+	switch f := f.(type) {
+	case Atom:
+		left, err := Eval(f.Left, env)
+		if err != nil {
+			return false, nil, err
+		}
+		right, err := Eval(f.Right, env)
+		if err != nil {
+			return false, nil, err
+		}
+		return left.Cmp(right) == 0, map[Variable]*big.Int{}, nil
+	case Negation:
+		value, assignment, err := evalBounded(ctx, f.Formula, env, bound)
+		return !value, assignment, err
+	case Compound:
+		left, leftAssignment, err := evalBounded(ctx, f.Left, env, bound)
+		if err != nil {
+			return false, nil, err
+		}
+
+		// The left side alone may decide the result, in which case it
+		// alone explains it.
+		switch {
+		case f.Kind == AND && !left,
+			f.Kind == OR && left,
+			f.Kind == IF_THEN && !left:
+			return f.Kind != AND, leftAssignment, nil
+		}
+
+		right, rightAssignment, err := evalBounded(ctx, f.Right, env, bound)
+		if err != nil {
+			return false, nil, err
+		}
+		if right == (f.Kind == AND) {
+			// both sides are needed to explain the result
+			for v, value := range rightAssignment {
+				leftAssignment[v] = value
+			}
+			return right, leftAssignment, nil
+		}
+		return right, rightAssignment, nil
+	case Quantification:
+		previous, shadowed := env[f.Variable]
+		defer func() {
+			if shadowed {
+				env[f.Variable] = previous
+			} else {
+				delete(env, f.Variable)
+			}
+		}()
+
+		// ∃ is decided by the first true value, ∀ by the first false
+		decisive := f.Kind == THERE_EXISTS
+		for i := uint64(0); i <= bound; i++ {
+			if err := ctx.Err(); err != nil {
+				return false, nil, err
+			}
+			value := new(big.Int).SetUint64(i)
+			env[f.Variable] = value
+			result, assignment, err := evalBounded(ctx, f.Formula, env, bound)
+			if err != nil {
+				return false, nil, err
+			}
+			if result == decisive {
+				assignment[f.Variable] = value
+				return result, assignment, nil
+			}
+			if i == bound {
+				// avoid overflow when bound is the maximum uint64
+				break
+			}
+		}
+		return !decisive, map[Variable]*big.Int{}, nil
+	}
+	return false, nil, fmt.Errorf("unknown formula type %T", f)
+}
@@ -0,0 +1,158 @@
+package tnt
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func mustParseTerm(t *testing.T, src string) Term {
+	t.Helper()
+	term, err := ParseTerm(src)
+	if err != nil {
+		t.Fatalf("error parsing %q: %s", src, err)
+	}
+	return term
+}
+
+func TestEval(t *testing.T) {
+	env := map[Variable]*big.Int{
+		"a":  big.NewInt(4),
+		"b'": big.NewInt(10),
+	}
+
+	for src, expected := range map[string]int64{
+		"0":                 0,
+		"SSS0":              3,
+		"a":                 4,
+		"SSa":               6,
+		"(SS0·(S0+SSS0))":   8,
+		"S(a·(b'+S0))":      45,
+		"((a+a)·(a·SSSa))":  224,
+		"(SSSSSSSSSS0·b')":  100,
+		"S(0+(SS0·SSa))":    13,
+		"((S0+S0)·(0+S0))":  2,
+		"SSSS(0·(a+SSSb'))": 4,
+	} {
+		got, err := Eval(mustParseTerm(t, src), env)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", src, err)
+		} else if got.Cmp(big.NewInt(expected)) != 0 {
+			t.Errorf("%q: expected %d but got %s", src, expected, got)
+		}
+	}
+
+	if got := env["a"].Int64(); got != 4 {
+		t.Errorf("expected env to be unmodified, but a is %d", got)
+	}
+}
+
+func TestEvalArbitraryPrecision(t *testing.T) {
+	// ((a·a)·(a·a)) with a = 2^40 is 2^160
+	a := new(big.Int).Lsh(big.NewInt(1), 40)
+	got, err := Eval(mustParseTerm(t, "((a·a)·(a·a))"), map[Variable]*big.Int{"a": a})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := new(big.Int).Lsh(big.NewInt(1), 160)
+	if got.Cmp(expected) != 0 {
+		t.Errorf("expected %s but got %s", expected, got)
+	}
+}
+
+func TestEvalUnbound(t *testing.T) {
+	_, err := Eval(mustParseTerm(t, "(S0+Sc)"), map[Variable]*big.Int{"a": big.NewInt(1)})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if expected := "variable c is unbound"; err.Error() != expected {
+		t.Errorf("expected error %q but got %q", expected, err)
+	}
+}
+
+func TestEvalBounded(t *testing.T) {
+	type testCase struct {
+		Formula    string
+		Env        map[Variable]*big.Int
+		Expected   bool
+		Assignment map[Variable]int64
+	}
+
+	for _, test := range []testCase{
+		{"S0=S0", nil, true, map[Variable]int64{}},
+		{"~(S0+S0)=SS0", nil, false, map[Variable]int64{}},
+		{"(a·a)=SSSS0", map[Variable]*big.Int{"a": big.NewInt(2)}, true,
+			map[Variable]int64{}},
+		{"∀a:(a+0)=a", nil, true, map[Variable]int64{}},
+		{"∃a:(a·a)=SSSSSSSSS0", nil, true, map[Variable]int64{"a": 3}},
+		{"∃a:(a·a)=SS0", nil, false, map[Variable]int64{}},
+		{"∀a:~(a·a)=SSSS0", nil, false, map[Variable]int64{"a": 2}},
+		{"∀a:(a·a)=a", nil, false, map[Variable]int64{"a": 2}},
+		{"∀a:∃b:(a+b)=SSS0", nil, false, map[Variable]int64{"a": 4}},
+		{"∀a:∃b:<(a+b)=SSSS0∨(b+SSSS0)=a>", nil, true, map[Variable]int64{}},
+		{"∃a:∃b:<~a=b∧(a·b)=SSSSSS0>", nil, true,
+			map[Variable]int64{"a": 2, "b": 3}},
+		{"<0=0⊃∀a:Sa=SSa>", nil, false, map[Variable]int64{"a": 0}},
+		{"<∃a:Sa=0⊃0=S0>", nil, true, map[Variable]int64{}},
+		{"<∃a:Sa=SS0∧∃b:SSb=SSS0>", nil, true,
+			map[Variable]int64{"a": 1, "b": 1}},
+		{"<∃a:Sa=SS0∧∃b:SSb=0>", nil, false, map[Variable]int64{}},
+		{"∀c:<(c·c)=c⊃<c=0∨c=S0>>", nil, true, map[Variable]int64{}},
+	} {
+		formula, err := ParseFormula(test.Formula)
+		if err != nil {
+			t.Fatalf("error parsing %q: %s", test.Formula, err)
+		}
+		got, assignment, err := EvalBounded(formula, test.Env, 4)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", test.Formula, err)
+			continue
+		}
+		if got != test.Expected {
+			t.Errorf("%q: expected %t but got %t",
+				test.Formula, test.Expected, got)
+		}
+
+		gotAssignment := make(map[Variable]int64)
+		for v, value := range assignment {
+			gotAssignment[v] = value.Int64()
+		}
+		if !reflect.DeepEqual(gotAssignment, test.Assignment) {
+			t.Errorf("%q: expected assignment %v but got %v",
+				test.Formula, test.Assignment, gotAssignment)
+		}
+	}
+}
+
+func TestEvalBoundedUnbound(t *testing.T) {
+	formula, err := ParseFormula("∀a:(a+b)=a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := EvalBounded(formula, nil, 3); err == nil {
+		t.Error("expected error for free variable b")
+	}
+
+	// Evaluation of these stops before reaching the unbound Variable.
+	for _, src := range []string{"<0=S0∧a=0>", "∃a:<a=0∨b=0>", "<0=0∨∀c:c=b>"} {
+		formula := mustParse(t, src)
+		_, _, err := EvalBounded(formula, nil, 3)
+		if err == nil || !strings.HasSuffix(err.Error(), "is unbound") {
+			t.Errorf("%s: expected an unbound variable but got %v", src, err)
+		}
+	}
+}
+
+func TestEvalBoundedContext(t *testing.T) {
+	formula, err := ParseFormula("∀a:∀b:∀c:∀d:(a+b)=(c+d)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := EvalBoundedContext(ctx, formula, nil, 1000); err != context.Canceled {
+		t.Errorf("expected evaluation to be canceled but got %v", err)
+	}
+}
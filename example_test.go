@@ -17,3 +17,30 @@ func Example() {
 	// open: true
 	// well formed: true
 }
+
+func ExampleInspect() {
+	formula, _ := tnt.ParseFormula("∀a:<~a=0⊃∃b:Sb=a>")
+	tnt.Inspect(formula, func(n tnt.Node) bool {
+		if q, ok := n.(tnt.Quantification); ok {
+			fmt.Println(q.Kind, q.Variable)
+		}
+		return true
+	})
+	// Output: FOR_ALL a
+	// THERE_EXISTS b
+}
+
+func ExampleRegisterAxioms() {
+	// ∀a:(0+a)=a is a theorem of TNT, but proving it takes induction.
+	// Adding it as an axiom lets derivations state it directly, without
+	// changing what can be proven.
+	shortcuts := append(tnt.AxiomSet{}, tnt.Axioms...)
+	extra, _ := tnt.ParseFormula("∀a:(0+a)=a")
+	shortcuts = append(shortcuts, extra)
+	tnt.RegisterAxioms("shortcuts", shortcuts)
+
+	set, _ := tnt.LookupAxioms("shortcuts")
+	n, _ := set.Find(extra)
+	fmt.Println("axiom", n)
+	// Output: axiom 6
+}
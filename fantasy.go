@@ -0,0 +1,106 @@
+package tnt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rules that Check handles itself, since they depend on the structure of
+// fantasies rather than on the Formulas alone.
+const (
+	// PUSH enters a fantasy.  The Step has no Formula.
+	PUSH = "push into fantasy"
+	// POP leaves a fantasy.  The Step has no Formula.
+	POP = "pop out of fantasy"
+	// PREMISE is the first Step of a fantasy, which may be any Formula.
+	PREMISE = "premise"
+	// CARRY_OVER copies a theorem from the fantasy one level out.
+	CARRY_OVER = "carry over"
+	// FANTASY follows a POP, and concludes <x⊃y>, where x is the premise
+	// of the fantasy and y its last line.
+	FANTASY = "fantasy rule"
+)
+
+// Fantasy applies the fantasy rule: if y can be derived when x is
+// assumed, then <x⊃y> is a theorem.
+func Fantasy(x, y Formula) Formula {
+	return Compound{
+		Kind:  IF_THEN,
+		Left:  x,
+		Right: y,
+	}
+}
+
+// scope is a fantasy, or the top level of a Derivation.
+type scope struct {
+	parent  *scope
+	premise Formula
+	// last is the Formula of the most recent Step in the fantasy
+	last Formula
+}
+
+func (s *scope) context() Context {
+	var ctx Context
+	for ; s.parent != nil; s = s.parent {
+		ctx.Fantasies = append([]Formula{s.premise}, ctx.Fantasies...)
+	}
+	return ctx
+}
+
+// Push enters a fantasy with the given premise, adding a PUSH Step and
+// a PREMISE Step to d.  It returns the number of the PREMISE Step.
+func (d *Derivation) Push(premise Formula) int {
+	d.Add(nil, PUSH)
+	return d.Add(premise, PREMISE)
+}
+
+// Pop leaves the innermost fantasy of d, adding a POP Step and a
+// FANTASY Step with the Formula synthesized from the fantasy.  It
+// returns the number of the FANTASY Step.
+func (d *Derivation) Pop() (int, error) {
+	// Find the innermost open fantasy and its last line.
+	var open []*scope
+	current := &scope{}
+	for _, step := range d.Steps {
+		switch strings.ToLower(step.Rule) {
+		case PUSH:
+			open = append(open, current)
+			current = &scope{parent: current}
+		case POP:
+			if len(open) == 0 {
+				return 0, fmt.Errorf("derivation has too many %ss", POP)
+			}
+			current, open = open[len(open)-1], open[:len(open)-1]
+		case PREMISE:
+			current.premise = step.Formula
+			current.last = step.Formula
+		default:
+			current.last = step.Formula
+		}
+	}
+	if current.parent == nil {
+		return 0, fmt.Errorf("not in a fantasy")
+	}
+	if current.premise == nil {
+		return 0, fmt.Errorf("fantasy has no premise")
+	}
+
+	d.Add(nil, POP)
+	return d.Add(Fantasy(current.premise, current.last), FANTASY), nil
+}
+
+// Depth returns the number of fantasies that are open at the end of d.
+func (d Derivation) Depth() int {
+	depth := 0
+	for _, step := range d.Steps {
+		switch strings.ToLower(step.Rule) {
+		case PUSH:
+			depth++
+		case POP:
+			if depth > 0 {
+				depth--
+			}
+		}
+	}
+	return depth
+}
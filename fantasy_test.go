@@ -0,0 +1,227 @@
+package tnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFantasy(t *testing.T) {
+	registerTestRules(t)
+	var d Derivation
+	given := d.Add(mustParse(t, "<a=0∧b=0>"), "test given")
+
+	d.Push(mustParse(t, "c=0"))
+	carried := d.Add(mustParse(t, "<a=0∧b=0>"), CARRY_OVER, given)
+	separated := d.Add(mustParse(t, "a=0"), "separation", carried)
+
+	d.Push(mustParse(t, "d=0"))
+	if depth := d.Depth(); depth != 2 {
+		t.Errorf("expected depth 2 but got %d", depth)
+	}
+	d.Add(mustParse(t, "a=0"), CARRY_OVER, separated)
+	inner, err := d.Pop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	outer, err := d.Pop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Add(mustParse(t, "a=0"), "separation", given)
+
+	if err := Check(d); err != nil {
+		t.Fatal(err)
+	}
+
+	for n, expected := range map[int]string{
+		inner: "<d=0⊃a=0>",
+		outer: "<c=0⊃<d=0⊃a=0>>",
+	} {
+		step, _ := d.Step(n)
+		if step.Formula.String() != expected || step.Rule != FANTASY {
+			t.Errorf("expected step %d to be %s by %s, but got %+v",
+				n, expected, FANTASY, step)
+		}
+	}
+	if depth := d.Depth(); depth != 0 {
+		t.Errorf("expected depth 0 but got %d", depth)
+	}
+
+	if _, err := d.Pop(); err == nil {
+		t.Error("expected error popping outside a fantasy")
+	}
+}
+
+func TestFantasyContext(t *testing.T) {
+	var got []Formula
+	registerRule(t, "test context", func(ctx Context, premises []Formula, conclusion Formula) error {
+		got = ctx.Fantasies
+		return nil
+	})
+
+	var d Derivation
+	d.Push(mustParse(t, "a=0"))
+	d.Push(mustParse(t, "b=0"))
+	d.Add(mustParse(t, "0=0"), "test context")
+	if err := Check(d); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Formula{mustParse(t, "a=0"), mustParse(t, "b=0")}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected fantasies %v but got %v", expected, got)
+	}
+}
+
+func TestFantasyRuleCase(t *testing.T) {
+	registerTestRules(t)
+	d := Derivation{Steps: []Step{
+		{Formula: mustParse(t, "a=0"), Rule: "test given"},
+		{Rule: "Push Into Fantasy"},
+		{Formula: mustParse(t, "b=0"), Rule: "Premise"},
+		{Formula: mustParse(t, "a=0"), Rule: "Carry Over", Premises: []int{1}},
+		{Rule: "Push into fantasy"},
+	}}
+	if depth := d.Depth(); depth != 2 {
+		t.Errorf("expected depth 2 but got %d", depth)
+	}
+	d.Steps = append(d.Steps[:4], Step{Rule: "POP OUT OF FANTASY"},
+		Step{Formula: mustParse(t, "<b=0⊃a=0>"), Rule: "Fantasy Rule"})
+	if err := Check(d); err != nil {
+		t.Fatal(err)
+	}
+
+	d.Steps = d.Steps[:4]
+	if _, err := d.Pop(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFantasyInvalid(t *testing.T) {
+	registerTestRules(t)
+	push := Step{Rule: PUSH}
+	pop := Step{Rule: POP}
+	step := func(f string, rule string, premises ...int) Step {
+		return Step{Formula: mustParse(t, f), Rule: rule, Premises: premises}
+	}
+
+	for name, test := range map[string]struct {
+		Steps []Step
+		Error string
+	}{
+		"outer step without carry over": {
+			Steps: []Step{
+				step("<a=0∧b=0>", "test given"),
+				push,
+				step("c=0", PREMISE),
+				step("a=0", "separation", 1),
+			},
+			Error: "step 4: premise 1 is not in the same fantasy",
+		},
+		"carry over from two levels out": {
+			Steps: []Step{
+				step("a=0", "test given"),
+				push,
+				step("b=0", PREMISE),
+				push,
+				step("c=0", PREMISE),
+				step("a=0", CARRY_OVER, 1),
+			},
+			Error: "step 6: carry over: step 1 is not in the enclosing fantasy",
+		},
+		"carry over from the same level": {
+			Steps: []Step{
+				step("a=0", "test given"),
+				step("a=0", CARRY_OVER, 1),
+			},
+			Error: "step 2: carry over: not in a fantasy",
+		},
+		"carry over changes formula": {
+			Steps: []Step{
+				step("a=0", "test given"),
+				push,
+				step("b=0", PREMISE),
+				step("b=0", CARRY_OVER, 1),
+			},
+			Error: "step 4: expected a=0 but got b=0",
+		},
+		"step from closed fantasy": {
+			Steps: []Step{
+				push,
+				step("<a=0∧b=0>", PREMISE),
+				pop,
+				step("<<a=0∧b=0>⊃<a=0∧b=0>>", FANTASY),
+				step("a=0", "separation", 2),
+			},
+			Error: "step 5: premise 2 is not in the same fantasy",
+		},
+		"premise not first": {
+			Steps: []Step{
+				push,
+				step("a=0", "test given"),
+			},
+			Error: "step 2: the first step of a fantasy must be its premise",
+		},
+		"premise outside fantasy": {
+			Steps: []Step{
+				step("a=0", PREMISE),
+			},
+			Error: "step 1: a premise must directly follow [",
+		},
+		"second premise": {
+			Steps: []Step{
+				push,
+				step("a=0", PREMISE),
+				step("b=0", PREMISE),
+			},
+			Error: "step 3: a premise must directly follow [",
+		},
+		"wrong conclusion": {
+			Steps: []Step{
+				push,
+				step("a=0", PREMISE),
+				step("~~a=0", "double-tilde", 2),
+				pop,
+				step("<a=0⊃a=0>", FANTASY),
+			},
+			Error: "step 5: expected <a=0⊃~~a=0> but got <a=0⊃a=0>",
+		},
+		"fantasy rule without pop": {
+			Steps: []Step{
+				push,
+				step("a=0", PREMISE),
+				step("<a=0⊃a=0>", FANTASY),
+			},
+			Error: "step 3: fantasy rule: must directly follow ]",
+		},
+		"pop outside fantasy": {
+			Steps: []Step{pop},
+			Error: "step 1: not in a fantasy",
+		},
+		"fantasy before premise": {
+			Steps: []Step{
+				push,
+				push,
+				step("a=0", PREMISE),
+				pop,
+				step("<a=0⊃a=0>", FANTASY),
+			},
+			Error: "step 2: the first step of a fantasy must be its premise",
+		},
+		"empty fantasy": {
+			Steps: []Step{push, pop},
+			Error: "step 2: fantasy has no premise",
+		},
+		"push with formula": {
+			Steps: []Step{{Formula: mustParse(t, "0=0"), Rule: PUSH}},
+			Error: "step 1: unexpected formula 0=0",
+		},
+	} {
+		err := Check(Derivation{Steps: test.Steps})
+		if err == nil {
+			t.Errorf("%s: expected error", name)
+		} else if err.Error() != test.Error {
+			t.Errorf("%s: expected error %q but got %q", name, test.Error, err)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package tnt
+
+import (
+	"math/big"
+
+	"github.com/jeremyhuiskamp/tnt/token"
+)
+
+// GodelNumber returns the Gödel number of f, using the codons from
+// Chapter 9 of the book.  It panics if f cannot be numbered, which only
+// happens if f was built with a Variable whose name is not a variable of
+// TNT, such as Variable("x"); use GodelNumbering to get an error
+// instead.
+func GodelNumber(f Formula) *big.Int {
+	g, err := GodelNumbering(f, token.Codons)
+	if err != nil {
+		panic("tnt: " + err.Error())
+	}
+	return g
+}
+
+// GodelNumbering returns the Gödel number of f in the Numbering n.  An
+// error is returned if f contains a Variable whose name is not a
+// variable of TNT, or a symbol that has no codon in n.
+func GodelNumbering(f Formula, n token.Numbering) (*big.Int, error) {
+	for _, v := range f.Variables().sorted() {
+		if _, err := parseVariableName(string(v)); err != nil {
+			return nil, err
+		}
+	}
+	return n.Encode(f.String())
+}
+
+// FromGodelNumber returns the Formula whose Gödel number is g, using the
+// codons from Chapter 9 of the book.  An error is returned if g does not
+// consist of codons, or if the symbols do not form a Formula.
+func FromGodelNumber(g *big.Int) (Formula, error) {
+	return FromGodelNumbering(g, token.Codons)
+}
+
+// FromGodelNumbering is like FromGodelNumber, but uses an alternative
+// Numbering.
+func FromGodelNumbering(g *big.Int, n token.Numbering) (Formula, error) {
+	src, err := n.Decode(g)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFormula(src)
+}
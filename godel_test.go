@@ -0,0 +1,77 @@
+package tnt
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/jeremyhuiskamp/tnt/token"
+)
+
+func TestGodelNumber(t *testing.T) {
+	for _, test := range []struct {
+		Formula, Number string
+		// Decoded is the Formula that Number decodes to.
+		Decoded string
+	}{
+		{"0=0", "666111666", "0=0"},
+		{"SS0=(S0+S0)", "123123666111362123666112123666323", "SS0=(S0+S0)"},
+		{"~∃a:(a·a)=S0", "223333262636362262236262323111123666", "~∃a:(a·a)=S0"},
+		{"∀b:<b=0∨~b=0>", "626262163636212262163111666616223262163111666213", "∀a':<a'=0∨~a'=0>"},
+	} {
+		formula, err := ParseFormula(test.Formula)
+		if err != nil {
+			t.Fatalf("error parsing %q: %s", test.Formula, err)
+		}
+
+		g := GodelNumber(formula)
+		if g.String() != test.Number {
+			t.Errorf("%q: expected %s but got %s", test.Formula, test.Number, g)
+		}
+
+		decoded, err := FromGodelNumber(g)
+		if err != nil {
+			t.Errorf("%q: unexpected error decoding %s: %s", test.Formula, g, err)
+		} else if decoded.String() != test.Decoded {
+			t.Errorf("%q: expected %s to decode to %s but got %s",
+				test.Formula, g, test.Decoded, decoded)
+		}
+	}
+}
+
+func TestGodelNumbering(t *testing.T) {
+	formula, err := ParseFormula("∀b:<b=0∨~c'=0>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := GodelNumbering(formula, token.ExtendedCodons)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := FromGodelNumbering(g, token.ExtendedCodons)
+	if err != nil || !reflect.DeepEqual(decoded, formula) {
+		t.Errorf("expected %s to decode to %s but got %v, %v", g, formula, decoded, err)
+	}
+
+	for _, f := range []Formula{
+		Atom{Variable("x"), Numeral(0)},
+		Atom{Variable("a=0"), Numeral(0)},
+	} {
+		if g, err := GodelNumbering(f, token.Codons); err == nil {
+			t.Errorf("%#v: expected error but got %s", f, g)
+		}
+	}
+}
+
+func TestFromGodelNumberInvalid(t *testing.T) {
+	for _, digits := range []string{
+		"666111",    // 0=
+		"666111999", // unknown codon
+		"66611166",  // not made of codons
+	} {
+		g, _ := new(big.Int).SetString(digits, 10)
+		if f, err := FromGodelNumber(g); err == nil {
+			t.Errorf("%s: expected error but got %s", digits, f)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package tnt
+
+import "fmt"
+
+func init() {
+	RegisterRule("induction", induction)
+}
+
+// Induct applies the rule of induction: if ∀u:<X⊃X{Su/u}> and X{0/u}
+// are theorems, then so is ∀u:X.  The induction Variable u and X are
+// taken from step, and u must be free in X.
+func Induct(base, step Formula) (Formula, error) {
+	q, ok := step.(Quantification)
+	if ok && q.Kind == FOR_ALL {
+		if c, ok := q.Formula.(Compound); ok && c.Kind == IF_THEN {
+			conclusion := Quantification{
+				Kind:     FOR_ALL,
+				Variable: q.Variable,
+				Formula:  c.Left,
+			}
+			if err := CheckInduction(base, step, conclusion); err != nil {
+				return nil, err
+			}
+			return conclusion, nil
+		}
+	}
+	return nil, fmt.Errorf("step case: %s is not of the form ∀u:<X⊃X{Su/u}>", step)
+}
+
+// CheckInduction verifies that conclusion ∀u:X follows by induction from
+// base and step, where u must be free in X.  The error says whether the base case X{0/u} or the
+// step case ∀u:<X⊃X{Su/u}> does not match.
+func CheckInduction(base, step, conclusion Formula) error {
+	expectedBase, expectedStep, err := inductionHypotheses(conclusion)
+	if err != nil {
+		return err
+	}
+	if !Equal(base, expectedBase) {
+		return fmt.Errorf("base case: expected %s but got %s", expectedBase, base)
+	}
+	if !Equal(step, expectedStep) {
+		return fmt.Errorf("step case: expected %s but got %s", expectedStep, step)
+	}
+	return nil
+}
+
+// inductionHypotheses returns the base case and step case from which
+// conclusion follows by induction.
+func inductionHypotheses(conclusion Formula) (base, step Formula, err error) {
+	q, ok := conclusion.(Quantification)
+	if !ok || q.Kind != FOR_ALL {
+		return nil, nil, fmt.Errorf("%s is not of the form ∀u:X", conclusion)
+	}
+	u, x := q.Variable, q.Formula
+	if _, ok := x.FreeVariables()[u]; !ok {
+		return nil, nil, fmt.Errorf("%s is not free in %s", u, x)
+	}
+
+	if base, err = Substitute(x, u, Numeral(0)); err != nil {
+		return nil, nil, err
+	}
+	next, err := Substitute(x, u, Successor{Quantity: 1, Term: u})
+	if err != nil {
+		return nil, nil, err
+	}
+	step = Quantification{
+		Kind:     FOR_ALL,
+		Variable: u,
+		Formula: Compound{
+			Kind:  IF_THEN,
+			Left:  x,
+			Right: next,
+		},
+	}
+	return base, step, nil
+}
+
+func induction(_ Context, premises []Formula, conclusion Formula) error {
+	if err := expectPremises(premises, 2); err != nil {
+		return err
+	}
+	base, step := premises[0], premises[1]
+	if _, expectedStep, err := inductionHypotheses(conclusion); err == nil &&
+		Equal(base, expectedStep) {
+		// The premises are given in the other order.
+		base, step = step, base
+	}
+	return CheckInduction(base, step, conclusion)
+}
@@ -0,0 +1,80 @@
+package tnt
+
+import "testing"
+
+func TestInduct(t *testing.T) {
+	f, err := Induct(mustParse(t, "(0+0)=0"), mustParse(t, "∀a:<(0+a)=a⊃(0+Sa)=Sa>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := f.String(); got != "∀a:(0+a)=a" {
+		t.Errorf("expected ∀a:(0+a)=a but got %s", got)
+	}
+
+	for _, test := range []struct {
+		base, step string
+		err        string
+	}{
+		{"(0+0)=0", "∀a:<(0+a)=a⊃(0+a)=Sa>",
+			"step case: expected ∀a:<(0+a)=a⊃(0+Sa)=Sa> but got ∀a:<(0+a)=a⊃(0+a)=Sa>"},
+		{"(0+S0)=S0", "∀a:<(0+a)=a⊃(0+Sa)=Sa>",
+			"base case: expected (0+0)=0 but got (0+S0)=S0"},
+		{"(0+0)=0", "∀a:(0+a)=a",
+			"step case: ∀a:(0+a)=a is not of the form ∀u:<X⊃X{Su/u}>"},
+		{"0=0", "∀a:<0=0⊃0=0>", "a is not free in 0=0"},
+	} {
+		_, err := Induct(mustParse(t, test.base), mustParse(t, test.step))
+		if err == nil || err.Error() != test.err {
+			t.Errorf("expected error %q but got %v", test.err, err)
+		}
+	}
+}
+
+func TestInduction(t *testing.T) {
+	base := "<∀b:(0·b)=0∨0=S0>"
+	step := "∀a:<<∀b:(a·b)=0∨a=S0>⊃<∀b:(Sa·b)=0∨Sa=S0>>"
+	conclusion := "∀a:<∀b:(a·b)=0∨a=S0>"
+
+	checkRule(t, "induction", []string{base, step}, conclusion, "")
+	checkRule(t, "induction", []string{step, base}, conclusion, "")
+	checkRule(t, "induction", []string{base, step}, "∃a:<∀b:(a·b)=0∨a=S0>",
+		"∃a:<∀b:(a·b)=0∨a=S0> is not of the form ∀u:X")
+	checkRule(t, "induction", []string{base, step}, "∀a:<∀b:(a·b)=0∨a=0>",
+		"base case: expected <∀b:(0·b)=0∨0=0> but got <∀b:(0·b)=0∨0=S0>")
+	checkRule(t, "induction", []string{base, "∀a:<<∀b:(a·b)=0∨a=S0>⊃<∀b:(a·b)=0∨Sa=S0>>"},
+		conclusion,
+		"step case: expected "+step+" but got ∀a:<<∀b:(a·b)=0∨a=S0>⊃<∀b:(a·b)=0∨Sa=S0>>")
+	checkRule(t, "induction", []string{"b=0", "∀a:<b=0⊃b=0>"}, "∀a:b=0",
+		"a is not free in b=0")
+	if err := CheckInduction(mustParse(t, "b=0"), mustParse(t, "∀a:<b=0⊃b=0>"),
+		mustParse(t, "∀a:b=0")); err == nil || err.Error() != "a is not free in b=0" {
+		t.Errorf("expected a not to be free but got %v", err)
+	}
+}
+
+// TestInductionDerivation proves ∀b:(0+b)=b from axioms 2 and 3.
+func TestInductionDerivation(t *testing.T) {
+	registerTestRules(t)
+	var d Derivation
+	axiom2 := d.Add(mustParse(t, "∀a:(a+0)=a"), "test given")
+	axiom3 := d.Add(mustParse(t, "∀a:∀b:(a+Sb)=S(a+b)"), "test given")
+	base := d.Add(mustParse(t, "(0+0)=0"), "specification", axiom2)
+	specified := d.Add(mustParse(t, "∀b:(0+Sb)=S(0+b)"), "specification", axiom3)
+	specified = d.Add(mustParse(t, "(0+Sb)=S(0+b)"), "specification", specified)
+
+	hypothesis := d.Push(mustParse(t, "(0+b)=b"))
+	added := d.Add(mustParse(t, "S(0+b)=Sb"), "add S", hypothesis)
+	carried := d.Add(mustParse(t, "(0+Sb)=S(0+b)"), CARRY_OVER, specified)
+	d.Add(mustParse(t, "(0+Sb)=Sb"), "transitivity", carried, added)
+	fantasy, err := d.Pop()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	step := d.Add(mustParse(t, "∀b:<(0+b)=b⊃(0+Sb)=Sb>"), "generalization", fantasy)
+	d.Add(mustParse(t, "∀b:(0+b)=b"), "induction", base, step)
+
+	if err := Check(d); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,491 @@
+package tnt
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// This file encodes Terms and Formulas as JSON.  Every node is an object
+// whose "kind" names its type, for example:
+//
+//	{"kind": "Atom",
+//	 "left": {"kind": "Variable", "name": "a"},
+//	 "right": {"kind": "Successor", "quantity": 1,
+//	           "term": {"kind": "Variable", "name": "b"}}}
+//
+// The kinds of CompoundTerms, Compounds and Quantifications are written
+// as the names of their constants, such as "PLUS".  Since a Term or
+// Formula field cannot be decoded without knowing its type, use
+// UnmarshalTerm and UnmarshalFormula to decode one.
+
+type numeralJSON struct {
+	Kind  string `json:"kind"`
+	Value int    `json:"value"`
+}
+
+type variableJSON struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+type successorJSON struct {
+	Kind     string          `json:"kind"`
+	Quantity int             `json:"quantity"`
+	Term     json.RawMessage `json:"term"`
+}
+
+type compoundTermJSON struct {
+	Kind     string            `json:"kind"`
+	Operator *CompoundTermKind `json:"operator"`
+	Left     json.RawMessage   `json:"left"`
+	Right    json.RawMessage   `json:"right"`
+}
+
+type atomJSON struct {
+	Kind  string          `json:"kind"`
+	Left  json.RawMessage `json:"left"`
+	Right json.RawMessage `json:"right"`
+}
+
+type negationJSON struct {
+	Kind    string          `json:"kind"`
+	Formula json.RawMessage `json:"formula"`
+}
+
+type compoundJSON struct {
+	Kind       string          `json:"kind"`
+	Connective *CompoundKind   `json:"connective"`
+	Left       json.RawMessage `json:"left"`
+	Right      json.RawMessage `json:"right"`
+}
+
+type quantificationJSON struct {
+	Kind       string              `json:"kind"`
+	Quantifier *QuantificationKind `json:"quantifier"`
+	Variable   string              `json:"variable"`
+	Formula    json.RawMessage     `json:"formula"`
+}
+
+// marshal encodes a Term or Formula for one of the structs above.
+func marshal(n Node) (json.RawMessage, error) {
+	if n == nil {
+		return nil, nil
+	}
+	return json.Marshal(n)
+}
+
+// marshalPair encodes the two sides of a binary node.
+func marshalPair(left, right Node) (json.RawMessage, json.RawMessage, error) {
+	l, err := marshal(left)
+	if err != nil {
+		return nil, nil, err
+	}
+	r, err := marshal(right)
+	if err != nil {
+		return nil, nil, err
+	}
+	return l, r, nil
+}
+
+func (n Numeral) MarshalJSON() ([]byte, error) {
+	return json.Marshal(numeralJSON{"Numeral", int(n)})
+}
+
+func (v Variable) MarshalJSON() ([]byte, error) {
+	return json.Marshal(variableJSON{"Variable", string(v)})
+}
+
+func (s Successor) MarshalJSON() ([]byte, error) {
+	term, err := marshal(s.Term)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(successorJSON{"Successor", s.Quantity, term})
+}
+
+func (c CompoundTerm) MarshalJSON() ([]byte, error) {
+	left, right, err := marshalPair(c.Left, c.Right)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(compoundTermJSON{"CompoundTerm", &c.Kind, left, right})
+}
+
+func (a Atom) MarshalJSON() ([]byte, error) {
+	left, right, err := marshalPair(a.Left, a.Right)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(atomJSON{"Atom", left, right})
+}
+
+func (n Negation) MarshalJSON() ([]byte, error) {
+	f, err := marshal(n.Formula)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(negationJSON{"Negation", f})
+}
+
+func (c Compound) MarshalJSON() ([]byte, error) {
+	left, right, err := marshalPair(c.Left, c.Right)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(compoundJSON{"Compound", &c.Kind, left, right})
+}
+
+func (q Quantification) MarshalJSON() ([]byte, error) {
+	f, err := marshal(q.Formula)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(quantificationJSON{"Quantification", &q.Kind, string(q.Variable), f})
+}
+
+// UnmarshalTerm decodes a Term of any kind.
+func UnmarshalTerm(data []byte) (Term, error) {
+	kind, err := peekKind(data)
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case "Numeral":
+		var n Numeral
+		err = n.UnmarshalJSON(data)
+		return n, err
+	case "Variable":
+		var v Variable
+		err = v.UnmarshalJSON(data)
+		return v, err
+	case "Successor":
+		var s Successor
+		err = s.UnmarshalJSON(data)
+		return s, err
+	case "CompoundTerm":
+		var c CompoundTerm
+		err = c.UnmarshalJSON(data)
+		return c, err
+	}
+	return nil, fmt.Errorf("%q is not a kind of term", kind)
+}
+
+// UnmarshalFormula decodes a Formula of any kind.
+func UnmarshalFormula(data []byte) (Formula, error) {
+	kind, err := peekKind(data)
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case "Atom":
+		var a Atom
+		err = a.UnmarshalJSON(data)
+		return a, err
+	case "Negation":
+		var n Negation
+		err = n.UnmarshalJSON(data)
+		return n, err
+	case "Compound":
+		var c Compound
+		err = c.UnmarshalJSON(data)
+		return c, err
+	case "Quantification":
+		var q Quantification
+		err = q.UnmarshalJSON(data)
+		return q, err
+	}
+	return nil, fmt.Errorf("%q is not a kind of formula", kind)
+}
+
+// peekKind returns the kind of the encoded node.
+func peekKind(data []byte) (string, error) {
+	var node struct {
+		Kind *string `json:"kind"`
+	}
+	if err := json.Unmarshal(data, &node); err != nil {
+		return "", err
+	}
+	if node.Kind == nil {
+		return "", fmt.Errorf("missing kind")
+	}
+	return *node.Kind, nil
+}
+
+// decode decodes data into v, checking that its kind is the expected
+// one.
+func decode(data []byte, expected string, v interface{}) error {
+	kind, err := peekKind(data)
+	if err != nil {
+		return err
+	}
+	if kind != expected {
+		return fmt.Errorf("expected kind %q but got %q", expected, kind)
+	}
+	return json.Unmarshal(data, v)
+}
+
+// unmarshalTerm decodes a required Term field.
+func unmarshalTerm(data json.RawMessage, field string) (Term, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, fmt.Errorf("missing %s", field)
+	}
+	return UnmarshalTerm(data)
+}
+
+// unmarshalFormula decodes a required Formula field.
+func unmarshalFormula(data json.RawMessage, field string) (Formula, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, fmt.Errorf("missing %s", field)
+	}
+	return UnmarshalFormula(data)
+}
+
+func (n *Numeral) UnmarshalJSON(data []byte) error {
+	var j numeralJSON
+	if err := decode(data, "Numeral", &j); err != nil {
+		return err
+	}
+	if j.Value < 0 {
+		return fmt.Errorf("numeral %d is negative", j.Value)
+	}
+	*n = Numeral(j.Value)
+	return nil
+}
+
+func (v *Variable) UnmarshalJSON(data []byte) error {
+	var j variableJSON
+	if err := decode(data, "Variable", &j); err != nil {
+		return err
+	}
+	variable, err := parseVariableName(j.Name)
+	if err != nil {
+		return err
+	}
+	*v = variable
+	return nil
+}
+
+// parseVariableName returns the Variable with the given name, or an
+// error if the name is not a Variable.
+func parseVariableName(name string) (Variable, error) {
+	if t, err := ParseTerm(name); err == nil {
+		if v, ok := t.(Variable); ok && string(v) == name {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("%q is not a variable", name)
+}
+
+func (s *Successor) UnmarshalJSON(data []byte) error {
+	var j successorJSON
+	if err := decode(data, "Successor", &j); err != nil {
+		return err
+	}
+	if j.Quantity < 1 {
+		return fmt.Errorf("successor quantity %d is not positive", j.Quantity)
+	}
+	term, err := unmarshalTerm(j.Term, "term")
+	if err != nil {
+		return err
+	}
+	*s = Successor{Quantity: j.Quantity, Term: term}
+	return nil
+}
+
+func (c *CompoundTerm) UnmarshalJSON(data []byte) error {
+	var j compoundTermJSON
+	if err := decode(data, "CompoundTerm", &j); err != nil {
+		return err
+	}
+	if j.Operator == nil {
+		return fmt.Errorf("missing operator")
+	}
+	left, err := unmarshalTerm(j.Left, "left")
+	if err != nil {
+		return err
+	}
+	right, err := unmarshalTerm(j.Right, "right")
+	if err != nil {
+		return err
+	}
+	*c = CompoundTerm{Kind: *j.Operator, Left: left, Right: right}
+	return nil
+}
+
+func (a *Atom) UnmarshalJSON(data []byte) error {
+	var j atomJSON
+	if err := decode(data, "Atom", &j); err != nil {
+		return err
+	}
+	left, err := unmarshalTerm(j.Left, "left")
+	if err != nil {
+		return err
+	}
+	right, err := unmarshalTerm(j.Right, "right")
+	if err != nil {
+		return err
+	}
+	*a = Atom{Left: left, Right: right}
+	return nil
+}
+
+func (n *Negation) UnmarshalJSON(data []byte) error {
+	var j negationJSON
+	if err := decode(data, "Negation", &j); err != nil {
+		return err
+	}
+	f, err := unmarshalFormula(j.Formula, "formula")
+	if err != nil {
+		return err
+	}
+	*n = Negation{Formula: f}
+	return nil
+}
+
+func (c *Compound) UnmarshalJSON(data []byte) error {
+	var j compoundJSON
+	if err := decode(data, "Compound", &j); err != nil {
+		return err
+	}
+	if j.Connective == nil {
+		return fmt.Errorf("missing connective")
+	}
+	left, err := unmarshalFormula(j.Left, "left")
+	if err != nil {
+		return err
+	}
+	right, err := unmarshalFormula(j.Right, "right")
+	if err != nil {
+		return err
+	}
+	*c = Compound{Kind: *j.Connective, Left: left, Right: right}
+	return nil
+}
+
+func (q *Quantification) UnmarshalJSON(data []byte) error {
+	var j quantificationJSON
+	if err := decode(data, "Quantification", &j); err != nil {
+		return err
+	}
+	if j.Quantifier == nil {
+		return fmt.Errorf("missing quantifier")
+	}
+	v, err := parseVariableName(j.Variable)
+	if err != nil {
+		return err
+	}
+	f, err := unmarshalFormula(j.Formula, "formula")
+	if err != nil {
+		return err
+	}
+	*q = Quantification{Kind: *j.Quantifier, Variable: v, Formula: f}
+	return nil
+}
+
+// MarshalText writes the name of the constant, such as PLUS.
+func (k CompoundTermKind) MarshalText() ([]byte, error) {
+	if k != PLUS && k != MULTIPLY {
+		return nil, fmt.Errorf("invalid %s", k)
+	}
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText reads the name of a constant, such as PLUS.
+func (k *CompoundTermKind) UnmarshalText(text []byte) error {
+	for _, kind := range []CompoundTermKind{PLUS, MULTIPLY} {
+		if string(text) == kind.String() {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown CompoundTermKind %q", text)
+}
+
+// MarshalText writes the name of the constant, such as AND.
+func (k CompoundKind) MarshalText() ([]byte, error) {
+	if k != AND && k != OR && k != IF_THEN {
+		return nil, fmt.Errorf("invalid %s", k)
+	}
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText reads the name of a constant, such as AND.
+func (k *CompoundKind) UnmarshalText(text []byte) error {
+	for _, kind := range []CompoundKind{AND, OR, IF_THEN} {
+		if string(text) == kind.String() {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown CompoundKind %q", text)
+}
+
+// MarshalText writes the name of the constant, such as FOR_ALL.
+func (k QuantificationKind) MarshalText() ([]byte, error) {
+	if k != THERE_EXISTS && k != FOR_ALL {
+		return nil, fmt.Errorf("invalid %s", k)
+	}
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText reads the name of a constant, such as FOR_ALL.
+func (k *QuantificationKind) UnmarshalText(text []byte) error {
+	for _, kind := range []QuantificationKind{THERE_EXISTS, FOR_ALL} {
+		if string(text) == kind.String() {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown QuantificationKind %q", text)
+}
+
+type stepJSON struct {
+	Formula  json.RawMessage `json:"formula,omitempty"`
+	Rule     string          `json:"rule"`
+	Premises []int           `json:"premises,omitempty"`
+}
+
+func (s Step) MarshalJSON() ([]byte, error) {
+	f, err := marshal(s.Formula)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(stepJSON{f, s.Rule, s.Premises})
+}
+
+func (s *Step) UnmarshalJSON(data []byte) error {
+	var j stepJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	*s = Step{Rule: j.Rule, Premises: j.Premises}
+	if len(j.Formula) != 0 && string(j.Formula) != "null" {
+		f, err := UnmarshalFormula(j.Formula)
+		if err != nil {
+			return err
+		}
+		s.Formula = f
+	}
+	return nil
+}
+
+func (s *AxiomSet) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw == nil {
+		*s = nil
+		return nil
+	}
+	set := make(AxiomSet, len(raw))
+	for i, r := range raw {
+		f, err := UnmarshalFormula(r)
+		if err != nil {
+			return fmt.Errorf("axiom %d: %s", i+1, err)
+		}
+		set[i] = f
+	}
+	*s = set
+	return nil
+}
@@ -0,0 +1,182 @@
+package tnt
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestFormulaJSON(t *testing.T) {
+	for _, src := range []string{
+		"0=0",
+		"a=SS0",
+		"~(a+Sb)=(c·0)",
+		"<a=0∧<b=0∨~c=0>>",
+		"<a=0⊃∃b:a=Sb>",
+		"∀a':∀b:(a'+Sb)=S(a'+b)",
+	} {
+		f, err := ParseFormula(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := json.Marshal(f)
+		if err != nil {
+			t.Errorf("%s: %s", src, err)
+			continue
+		}
+		got, err := UnmarshalFormula(data)
+		if err != nil {
+			t.Errorf("%s: %s: %s", src, err, data)
+			continue
+		}
+		if !reflect.DeepEqual(got, f) {
+			t.Errorf("expected %s but got %s from %s", f, got, data)
+		}
+	}
+}
+
+func TestFormulaJSONEncoding(t *testing.T) {
+	f, _ := ParseFormula("∀a:~(a+S0)=0")
+	data, err := json.Marshal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"kind":"Quantification","quantifier":"FOR_ALL","variable":"a",` +
+		`"formula":{"kind":"Negation","formula":{"kind":"Atom",` +
+		`"left":{"kind":"CompoundTerm","operator":"PLUS",` +
+		`"left":{"kind":"Variable","name":"a"},` +
+		`"right":{"kind":"Numeral","value":1}},` +
+		`"right":{"kind":"Numeral","value":0}}}}`
+	if string(data) != expected {
+		t.Errorf("expected %s but got %s", expected, data)
+	}
+}
+
+func TestTermJSON(t *testing.T) {
+	for _, term := range []Term{
+		Numeral(3),
+		Variable("b''"),
+		Successor{2, Variable("a")},
+		CompoundTerm{MULTIPLY, Numeral(2), Successor{1, Variable("c")}},
+	} {
+		data, err := json.Marshal(term)
+		if err != nil {
+			t.Errorf("%s: %s", term, err)
+			continue
+		}
+		got, err := UnmarshalTerm(data)
+		if err != nil {
+			t.Errorf("%s: %s: %s", term, err, data)
+			continue
+		}
+		if !reflect.DeepEqual(got, term) {
+			t.Errorf("expected %#v but got %#v from %s", term, got, data)
+		}
+	}
+}
+
+func TestKindJSON(t *testing.T) {
+	data, err := json.Marshal([]interface{}{PLUS, MULTIPLY, AND, OR, IF_THEN, THERE_EXISTS, FOR_ALL})
+	expected := `["PLUS","MULTIPLY","AND","OR","IF_THEN","THERE_EXISTS","FOR_ALL"]`
+	if err != nil || string(data) != expected {
+		t.Errorf("expected %s but got %s, %v", expected, data, err)
+	}
+
+	if _, err := json.Marshal(CompoundKind(7)); err == nil {
+		t.Errorf("expected an invalid CompoundKind not to be encoded")
+	}
+	zero := Atom{Numeral(0), Numeral(0)}
+	for _, n := range []Node{
+		Negation{Compound{7, zero, zero}},
+		Quantification{5, "a", zero},
+		Atom{Successor{1, CompoundTerm{3, Numeral(0), Numeral(0)}}, Numeral(0)},
+	} {
+		if data, err := json.Marshal(n); err == nil {
+			t.Errorf("expected %#v not to be encoded but got %s", n, data)
+		}
+	}
+	if _, err := json.Marshal(Step{Formula: Negation{Compound{7, zero, zero}}, Rule: "given"}); err == nil {
+		t.Errorf("expected a step with an invalid CompoundKind not to be encoded")
+	}
+	var k QuantificationKind
+	if err := json.Unmarshal([]byte(`"EXISTS"`), &k); err == nil {
+		t.Errorf("expected EXISTS not to be decoded, but got %s", k)
+	}
+}
+
+func TestUnmarshalJSONErrors(t *testing.T) {
+	for _, test := range []struct {
+		JSON, Error string
+	}{
+		{`{"kind":"Variable","name":"a"}`, `"Variable" is not a kind of formula`},
+		{`{"kind":"Atom"}`, "missing left"},
+		{`{"name":"a"}`, "missing kind"},
+		{`{"kind":"Atom","left":{"kind":"Atom"},"right":{"kind":"Numeral","value":0}}`,
+			`"Atom" is not a kind of term`},
+		{`{"kind":"Atom","left":{"kind":"Variable","name":"x"},"right":{"kind":"Numeral","value":0}}`,
+			`"x" is not a variable`},
+		{`{"kind":"Atom","left":{"kind":"Numeral","value":-1},"right":{"kind":"Numeral","value":0}}`,
+			"numeral -1 is negative"},
+		{`{"kind":"Atom","left":{"kind":"Successor","quantity":0,"term":{"kind":"Numeral","value":0}},"right":{"kind":"Numeral","value":0}}`,
+			"successor quantity 0 is not positive"},
+		{`{"kind":"Negation","formula":{"kind":"Atom","left":{"kind":"CompoundTerm","operator":"MINUS"}}}`,
+			`unknown CompoundTermKind "MINUS"`},
+		{`{"kind":"Quantification","quantifier":"FOR_ALL","variable":"a'b","formula":{"kind":"Atom"}}`,
+			`"a'b" is not a variable`},
+		{`{"kind":"Atom","left":{"kind":"CompoundTerm","left":{"kind":"Numeral","value":0},"right":{"kind":"Numeral","value":0}},"right":{"kind":"Numeral","value":0}}`,
+			"missing operator"},
+		{`{"kind":"Compound","left":{"kind":"Atom","left":{"kind":"Numeral","value":0},"right":{"kind":"Numeral","value":0}},"right":{"kind":"Atom","left":{"kind":"Numeral","value":0},"right":{"kind":"Numeral","value":0}}}`,
+			"missing connective"},
+		{`{"kind":"Quantification","quantifier":null,"variable":"a","formula":{"kind":"Atom","left":{"kind":"Variable","name":"a"},"right":{"kind":"Numeral","value":0}}}`,
+			"missing quantifier"},
+	} {
+		_, err := UnmarshalFormula([]byte(test.JSON))
+		if err == nil || err.Error() != test.Error {
+			t.Errorf("%s: expected error %q but got %v", test.JSON, test.Error, err)
+		}
+	}
+
+	var a Atom
+	if err := json.Unmarshal([]byte(`{"kind":"Negation"}`), &a); err == nil ||
+		err.Error() != `expected kind "Atom" but got "Negation"` {
+		t.Errorf("expected wrong kind but got %v", err)
+	}
+}
+
+func TestDerivationJSON(t *testing.T) {
+	d := Derivation{
+		Steps: []Step{
+			{Rule: PUSH},
+			{Formula: mustParseFormula("a=0"), Rule: PREMISE},
+			{Formula: mustParseFormula("a=0"), Rule: CARRY_OVER, Premises: []int{2}},
+			{Rule: POP},
+			{Formula: mustParseFormula("<a=0⊃a=0>"), Rule: FANTASY, Premises: []int{2, 3}},
+		},
+		Axioms: AxiomSet{mustParseFormula("∀a:a=a")},
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Derivation
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("%s: %s", err, data)
+	}
+	if !reflect.DeepEqual(got, d) {
+		t.Errorf("expected %+v but got %+v from %s", d, got, data)
+	}
+
+	data, _ = json.Marshal(Derivation{Steps: d.Steps[:1]})
+	if expected := `{"steps":[{"rule":"push into fantasy"}]}`; string(data) != expected {
+		t.Errorf("expected %s but got %s", expected, data)
+	}
+	got = Derivation{}
+	if err := json.Unmarshal(data, &got); err != nil || got.Axioms != nil {
+		t.Errorf("expected no axioms but got %v, %v", got.Axioms, err)
+	}
+
+	err = json.Unmarshal([]byte(`{"steps":[],"axioms":[{"kind":"Numeral","value":0}]}`), &got)
+	if expected := `axiom 1: "Numeral" is not a kind of formula`; err == nil || err.Error() != expected {
+		t.Errorf("expected error %q but got %v", expected, err)
+	}
+}
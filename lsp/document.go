@@ -0,0 +1,273 @@
+package lsp
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/jeremyhuiskamp/tnt"
+	"github.com/jeremyhuiskamp/tnt/derivation"
+	"github.com/jeremyhuiskamp/tnt/token"
+)
+
+// document is an open document and what is known about it.
+type document struct {
+	uri   string
+	lines [][]rune
+	// isDerivation is true for derivation files, and false for files of
+	// formulas, one per line.
+	isDerivation bool
+
+	// formulas are the formulas that parsed, in order
+	formulas []span
+	// positions locate the steps of a derivation that parsed
+	positions []derivation.Positions
+
+	diagnostics []Diagnostic
+}
+
+// span is a formula and where it appears on a line, as rune indices.
+type span struct {
+	line, start, end int
+	formula          tnt.Formula
+}
+
+// newDocument analyzes the text of a document.  Files with the .tnt
+// extension are derivations.
+func newDocument(uri, text string) *document {
+	doc := &document{
+		uri:          uri,
+		isDerivation: strings.HasSuffix(uri, ".tnt"),
+	}
+	for _, line := range strings.Split(text, "\n") {
+		doc.lines = append(doc.lines, []rune(strings.TrimSuffix(line, "\r")))
+	}
+
+	if doc.isDerivation {
+		doc.analyzeDerivation(text)
+	} else {
+		doc.analyzeFormulas()
+	}
+	for _, s := range doc.formulas {
+		for _, d := range tnt.CheckWellFormed(s.formula) {
+			doc.diagnose(doc.spanRange(s), "not well formed: %s", d)
+		}
+	}
+	if doc.diagnostics == nil {
+		doc.diagnostics = []Diagnostic{}
+	}
+	return doc
+}
+
+func (doc *document) diagnose(r Range, format string, args ...interface{}) {
+	doc.diagnostics = append(doc.diagnostics, Diagnostic{
+		Range:    r,
+		Severity: SeverityError,
+		Source:   "tnt",
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// diagnoseError reports an error from parsing.
+func (doc *document) diagnoseError(err error) {
+	var perr *tnt.ParseError
+	var derr *derivation.Error
+	switch {
+	case errors.As(err, &perr):
+		width := utf8.RuneCountInString(perr.Literal)
+		if width < 1 {
+			width = 1
+		}
+		doc.diagnose(doc.rangeAt(perr.Pos, width), "%s", strings.TrimPrefix(err.Error(), perr.Pos.String()+": "))
+	case errors.As(err, &derr):
+		doc.diagnose(doc.rangeAt(derr.Pos, 1), "%s", derr.Err)
+	default:
+		doc.diagnose(Range{}, "%s", err)
+	}
+}
+
+func (doc *document) analyzeFormulas() {
+	for i, line := range doc.lines {
+		text := string(line)
+		trimmed := strings.TrimSpace(text)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		f, err := tnt.ParseFormula(text)
+		if err != nil {
+			if perr, ok := err.(*tnt.ParseError); ok {
+				perr.Pos.Line = i + 1
+			}
+			doc.diagnoseError(err)
+			continue
+		}
+		start := len(line) - len([]rune(strings.TrimLeftFunc(text, unicode.IsSpace)))
+		end := len([]rune(strings.TrimRightFunc(text, unicode.IsSpace)))
+		doc.formulas = append(doc.formulas, span{i, start, end, f})
+	}
+}
+
+func (doc *document) analyzeDerivation(text string) {
+	d, positions, err := derivation.ParsePositions(strings.NewReader(text))
+	doc.positions = positions
+	for i, step := range d.Steps {
+		if step.Formula == nil {
+			continue
+		}
+		pos := positions[i]
+		line := pos.Formula.Line - 1
+		start := pos.Formula.Column - 1
+		// The formula ends before the parenthesis opening the rule.
+		end := pos.Rule.Column - 2
+		for end > start && doc.lines[line][end] != '(' {
+			end--
+		}
+		for end > start && unicode.IsSpace(doc.lines[line][end-1]) {
+			end--
+		}
+		doc.formulas = append(doc.formulas, span{line, start, end, step.Formula})
+	}
+
+	if err != nil {
+		doc.diagnoseError(err)
+		return
+	}
+	for _, s := range doc.formulas {
+		if !s.formula.WellFormed() {
+			// Check would report the same problem.
+			return
+		}
+	}
+
+	var stepErr *tnt.StepError
+	if err := tnt.Check(d); errors.As(err, &stepErr) {
+		pos := positions[stepErr.Step-1].Number
+		r := doc.rangeAt(pos, len(doc.lines[pos.Line-1])-(pos.Column-1))
+		doc.diagnose(r, "%s", err)
+	}
+}
+
+// character returns the UTF-16 offset of the rune at index i of a line.
+func (doc *document) character(line, i int) int {
+	if line < 0 || line >= len(doc.lines) {
+		return 0
+	}
+	runes := doc.lines[line]
+	if i > len(runes) {
+		i = len(runes)
+	}
+	return len(utf16.Encode(runes[:i]))
+}
+
+// index returns the index of the rune at a Position.
+func (doc *document) index(p Position) int {
+	if p.Line < 0 || p.Line >= len(doc.lines) {
+		return -1
+	}
+	units := 0
+	for i, r := range doc.lines[p.Line] {
+		if units >= p.Character {
+			return i
+		}
+		// Runes outside the Basic Multilingual Plane take two UTF-16
+		// code units.
+		if r >= 0x10000 {
+			units += 2
+		} else {
+			units++
+		}
+	}
+	return len(doc.lines[p.Line])
+}
+
+// rangeAt returns the Range of width runes starting at pos.
+func (doc *document) rangeAt(pos token.Position, width int) Range {
+	line, i := pos.Line-1, pos.Column-1
+	return Range{
+		Start: Position{line, doc.character(line, i)},
+		End:   Position{line, doc.character(line, i+width)},
+	}
+}
+
+func (doc *document) spanRange(s span) Range {
+	return Range{
+		Start: Position{s.line, doc.character(s.line, s.start)},
+		End:   Position{s.line, doc.character(s.line, s.end)},
+	}
+}
+
+// hover describes the formula at p.
+func (doc *document) hover(p Position) *Hover {
+	i := doc.index(p)
+	for _, s := range doc.formulas {
+		if s.line != p.Line || i < s.start || i >= s.end {
+			continue
+		}
+		r := doc.spanRange(s)
+		value := fmt.Sprintf("`%s`\n\nfree variables: %s", s.formula, s.formula.FreeVariables())
+		if g, err := tnt.GodelNumbering(s.formula, token.Codons); err == nil {
+			value += fmt.Sprintf("\n\nGödel number: %s", g)
+		}
+		return &Hover{
+			Contents: MarkupContent{Kind: "markdown", Value: value},
+			Range:    &r,
+		}
+	}
+	return nil
+}
+
+// definition returns the Location of the step referred to at p.
+func (doc *document) definition(p Position) *Location {
+	i := doc.index(p)
+	for _, pos := range doc.positions {
+		for _, premise := range pos.Premises {
+			line, start := premise.Line-1, premise.Column-1
+			if line != p.Line || i < start {
+				continue
+			}
+			end := start
+			for end < len(doc.lines[line]) && unicode.IsDigit(doc.lines[line][end]) {
+				end++
+			}
+			if i > end {
+				continue
+			}
+			n, _ := strconv.Atoi(string(doc.lines[line][start:end]))
+			if n < 1 || n > len(doc.positions) {
+				return nil
+			}
+			target := doc.positions[n-1].Number
+			targetLine := target.Line - 1
+			return &Location{
+				URI: doc.uri,
+				Range: Range{
+					Start: Position{targetLine, 0},
+					End:   Position{targetLine, doc.character(targetLine, len(doc.lines[targetLine]))},
+				},
+			}
+		}
+	}
+	return nil
+}
+
+// completions lists the rules that a step may be annotated with.
+func completions() []CompletionItem {
+	items := []CompletionItem{
+		{Label: tnt.AXIOM, Kind: CompletionKeyword, Detail: "an axiom, optionally by number"},
+		{Label: tnt.PREMISE, Kind: CompletionKeyword, Detail: "the premise of a fantasy"},
+		{Label: tnt.CARRY_OVER, Kind: CompletionKeyword, Detail: "a step from the enclosing fantasy"},
+		{Label: tnt.FANTASY, Kind: CompletionKeyword, Detail: "the conclusion of a fantasy"},
+	}
+	for _, name := range tnt.RuleNames() {
+		items = append(items, CompletionItem{
+			Label:  name,
+			Kind:   CompletionKeyword,
+			Detail: "rule of inference",
+		})
+	}
+	return items
+}
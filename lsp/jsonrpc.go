@@ -0,0 +1,124 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"sync"
+)
+
+// message is a JSON-RPC 2.0 request, response or notification.
+// Requests have an ID and a Method, notifications only a Method, and
+// responses only an ID.
+type message struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  json.RawMessage  `json:"result,omitempty"`
+	Error   *responseError   `json:"error,omitempty"`
+}
+
+// responseError is the error of a failed request.
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *responseError) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+// Error codes defined by JSON-RPC.
+const (
+	codeParseError     = -32700
+	codeInvalidParams  = -32602
+	codeMethodNotFound = -32601
+)
+
+// maxContentLength is the largest message body read, so that a bad
+// header cannot exhaust memory.
+const maxContentLength = 8 << 20
+
+// conn reads and writes messages framed by a Content-Length header, as
+// the Language Server Protocol requires.
+type conn struct {
+	r *textproto.Reader
+	w io.Writer
+	// mu serializes writes
+	mu sync.Mutex
+}
+
+func newConn(r io.Reader, w io.Writer) *conn {
+	return &conn{
+		r: textproto.NewReader(bufio.NewReader(r)),
+		w: w,
+	}
+}
+
+// read reads the next message.
+func (c *conn) read() (*message, error) {
+	header, err := c.r.ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Content-Length: %q", header.Get("Content-Length"))
+	}
+	if length < 0 || length > maxContentLength {
+		return nil, fmt.Errorf("invalid Content-Length: %d is not between 0 and %d",
+			length, maxContentLength)
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.r.R, body); err != nil {
+		return nil, err
+	}
+	var m message
+	if err := json.Unmarshal(body, &m); err != nil {
+		return nil, &responseError{Code: codeParseError, Message: err.Error()}
+	}
+	return &m, nil
+}
+
+// write writes m, filling in the protocol version.
+func (c *conn) write(m *message) error {
+	m.JSONRPC = "2.0"
+	body, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = c.w.Write(body)
+	return err
+}
+
+// notify writes a notification.
+func (c *conn) notify(method string, params interface{}) error {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return c.write(&message{Method: method, Params: raw})
+}
+
+// reply writes the response to the request with the given ID.
+func (c *conn) reply(id *json.RawMessage, result interface{}, rerr *responseError) error {
+	if rerr != nil {
+		return c.write(&message{ID: id, Error: rerr})
+	}
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return c.write(&message{ID: id, Result: raw})
+}
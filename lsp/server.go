@@ -0,0 +1,165 @@
+// Package lsp implements a Language Server Protocol server for TNT.
+//
+// Documents with the .tnt extension are derivations, in the layout read
+// by package derivation.  Other documents hold one formula per line.
+// The server publishes diagnostics for parse errors, formulas that are
+// not well formed and steps that do not follow; shows the free
+// variables and Gödel number of a formula on hover; goes from a step
+// reference to the referenced step; and completes rule names.
+//
+// Only full document synchronization is supported.
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// Server is a language server connected to one client.
+type Server struct {
+	conn *conn
+	docs map[string]*document
+}
+
+// NewServer returns a Server that reads messages from r and writes them
+// to w.
+func NewServer(r io.Reader, w io.Writer) *Server {
+	return &Server{
+		conn: newConn(r, w),
+		docs: make(map[string]*document),
+	}
+}
+
+// Serve handles messages until the client sends the exit notification
+// or closes the connection.
+func (s *Server) Serve() error {
+	for {
+		m, err := s.conn.read()
+		var rerr *responseError
+		if errors.As(err, &rerr) {
+			// The ID of a message that cannot be parsed is unknown, so
+			// the response has a null ID.
+			null := json.RawMessage("null")
+			if err := s.conn.reply(&null, nil, rerr); err != nil {
+				return err
+			}
+			continue
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if m.Method == "exit" {
+			return nil
+		}
+		if err := s.handle(m); err != nil {
+			return err
+		}
+	}
+}
+
+// Serve runs a Server on r and w.
+func Serve(r io.Reader, w io.Writer) error {
+	return NewServer(r, w).Serve()
+}
+
+// handle dispatches a request or notification.
+func (s *Server) handle(m *message) error {
+	if m.ID == nil {
+		return s.notification(m)
+	}
+
+	var result interface{}
+	var rerr *responseError
+	switch m.Method {
+	case "initialize":
+		result = InitializeResult{
+			Capabilities: ServerCapabilities{
+				TextDocumentSync:   1,
+				HoverProvider:      true,
+				DefinitionProvider: true,
+				CompletionProvider: &CompletionOptions{
+					TriggerCharacters: []string{"("},
+				},
+			},
+			ServerInfo: ServerInfo{Name: "tnt"},
+		}
+	case "shutdown":
+		result = nil
+	case "textDocument/hover":
+		var params TextDocumentPositionParams
+		if rerr = decode(m.Params, &params); rerr == nil {
+			if doc, ok := s.docs[params.TextDocument.URI]; ok {
+				if h := doc.hover(params.Position); h != nil {
+					result = h
+				}
+			}
+		}
+	case "textDocument/definition":
+		var params TextDocumentPositionParams
+		if rerr = decode(m.Params, &params); rerr == nil {
+			if doc, ok := s.docs[params.TextDocument.URI]; ok {
+				if l := doc.definition(params.Position); l != nil {
+					result = l
+				}
+			}
+		}
+	case "textDocument/completion":
+		result = completions()
+	default:
+		rerr = &responseError{
+			Code:    codeMethodNotFound,
+			Message: "method not found: " + m.Method,
+		}
+	}
+	return s.conn.reply(m.ID, result, rerr)
+}
+
+// notification handles a message that needs no response.  Unknown
+// notifications are ignored, as the protocol requires.
+func (s *Server) notification(m *message) error {
+	switch m.Method {
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if decode(m.Params, &params) == nil {
+			return s.update(params.TextDocument.URI, params.TextDocument.Text)
+		}
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if decode(m.Params, &params) == nil && len(params.ContentChanges) != 0 {
+			last := params.ContentChanges[len(params.ContentChanges)-1]
+			return s.update(params.TextDocument.URI, last.Text)
+		}
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if decode(m.Params, &params) == nil {
+			delete(s.docs, params.TextDocument.URI)
+			return s.conn.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+				URI:         params.TextDocument.URI,
+				Diagnostics: []Diagnostic{},
+			})
+		}
+	}
+	return nil
+}
+
+// update analyzes a new version of a document and publishes its
+// diagnostics.
+func (s *Server) update(uri, text string) error {
+	doc := newDocument(uri, text)
+	s.docs[uri] = doc
+	return s.conn.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: doc.diagnostics,
+	})
+}
+
+func decode(params json.RawMessage, v interface{}) *responseError {
+	if err := json.Unmarshal(params, v); err != nil {
+		return &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
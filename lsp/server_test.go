@@ -0,0 +1,320 @@
+package lsp
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// client talks to a Server running in the same process.
+type client struct {
+	t      *testing.T
+	conn   *conn
+	nextID int
+	// notifications holds notifications read while waiting for a
+	// response
+	notifications []*message
+	done          chan error
+}
+
+func newClient(t *testing.T) *client {
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+	c := &client{
+		t:    t,
+		conn: newConn(clientR, clientW),
+		done: make(chan error, 1),
+	}
+	go func() {
+		c.done <- Serve(serverR, serverW)
+		serverW.Close()
+	}()
+	return c
+}
+
+// call sends a request and decodes the result into result, returning
+// the error of the response.
+func (c *client) call(method string, params, result interface{}) *responseError {
+	c.t.Helper()
+	c.nextID++
+	id := json.RawMessage(strconv.Itoa(c.nextID))
+	raw, _ := json.Marshal(params)
+	if err := c.conn.write(&message{ID: &id, Method: method, Params: raw}); err != nil {
+		c.t.Fatal(err)
+	}
+	for {
+		m, err := c.conn.read()
+		if err != nil {
+			c.t.Fatal(err)
+		}
+		if m.ID == nil {
+			c.notifications = append(c.notifications, m)
+			continue
+		}
+		if string(*m.ID) != string(id) {
+			c.t.Fatalf("expected response %s but got %s", id, *m.ID)
+		}
+		if m.Error != nil {
+			return m.Error
+		}
+		if result != nil {
+			if err := json.Unmarshal(m.Result, result); err != nil {
+				c.t.Fatal(err)
+			}
+		}
+		return nil
+	}
+}
+
+func (c *client) notify(method string, params interface{}) {
+	c.t.Helper()
+	if err := c.conn.notify(method, params); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// diagnostics returns the next diagnostics published.
+func (c *client) diagnostics() PublishDiagnosticsParams {
+	c.t.Helper()
+	var m *message
+	if len(c.notifications) != 0 {
+		m, c.notifications = c.notifications[0], c.notifications[1:]
+	} else {
+		var err error
+		if m, err = c.conn.read(); err != nil {
+			c.t.Fatal(err)
+		}
+	}
+	if m.Method != "textDocument/publishDiagnostics" {
+		c.t.Fatalf("expected diagnostics but got %s", m.Method)
+	}
+	var params PublishDiagnosticsParams
+	if err := json.Unmarshal(m.Params, &params); err != nil {
+		c.t.Fatal(err)
+	}
+	return params
+}
+
+func (c *client) open(uri, text string) PublishDiagnosticsParams {
+	c.t.Helper()
+	c.notify("textDocument/didOpen", DidOpenTextDocumentParams{
+		TextDocument: TextDocumentItem{URI: uri, LanguageID: "tnt", Version: 1, Text: text},
+	})
+	return c.diagnostics()
+}
+
+func (c *client) close() {
+	c.t.Helper()
+	if err := c.call("shutdown", nil, nil); err != nil {
+		c.t.Fatal(err)
+	}
+	c.notify("exit", nil)
+	if err := <-c.done; err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func at(uri string, line, character int) TextDocumentPositionParams {
+	return TextDocumentPositionParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+		Position:     Position{line, character},
+	}
+}
+
+const proof = `1  ∀a:(a+0)=a     (axiom 2)
+2  (S0+0)=S0      (specification: 1)
+3  (S0+0)=0       (specification: 1)
+`
+
+func TestInitialize(t *testing.T) {
+	c := newClient(t)
+	defer c.close()
+
+	var result InitializeResult
+	if err := c.call("initialize", map[string]interface{}{}, &result); err != nil {
+		t.Fatal(err)
+	}
+	caps := result.Capabilities
+	if caps.TextDocumentSync != 1 || !caps.HoverProvider || !caps.DefinitionProvider ||
+		caps.CompletionProvider == nil {
+		t.Errorf("unexpected capabilities %+v", caps)
+	}
+
+	err := c.call("textDocument/rename", nil, nil)
+	if err == nil || err.Code != codeMethodNotFound {
+		t.Errorf("expected method not found but got %v", err)
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	c := newClient(t)
+	defer c.close()
+
+	published := c.open("file:///proof.tnt", proof)
+	expected := []Diagnostic{{
+		Range:    Range{Position{2, 0}, Position{2, 36}},
+		Severity: SeverityError,
+		Source:   "tnt",
+		Message:  "step 3: specification: (S0+0)=0 is not (a+0)=a with a term in place of a",
+	}}
+	if published.URI != "file:///proof.tnt" || !reflect.DeepEqual(published.Diagnostics, expected) {
+		t.Errorf("expected %+v but got %+v", expected, published)
+	}
+
+	c.notify("textDocument/didChange", DidChangeTextDocumentParams{
+		TextDocument:   TextDocumentIdentifier{URI: "file:///proof.tnt"},
+		ContentChanges: []TextDocumentContentChangeEvent{{Text: "1  ∀a:(a+0)=_  (axiom 2)\n"}},
+	})
+	expected = []Diagnostic{{
+		Range:    Range{Position{0, 12}, Position{0, 13}},
+		Severity: SeverityError,
+		Source:   "tnt",
+		Message:  `expected 0, S, VARIABLE or ( but got ILLEGAL "_"`,
+	}}
+	if published := c.diagnostics(); !reflect.DeepEqual(published.Diagnostics, expected) {
+		t.Errorf("expected %+v but got %+v", expected, published.Diagnostics)
+	}
+
+	c.notify("textDocument/didChange", DidChangeTextDocumentParams{
+		TextDocument:   TextDocumentIdentifier{URI: "file:///proof.tnt"},
+		ContentChanges: []TextDocumentContentChangeEvent{{Text: proof[:strings.Index(proof, "3")]}},
+	})
+	if published := c.diagnostics(); len(published.Diagnostics) != 0 {
+		t.Errorf("expected no diagnostics but got %+v", published.Diagnostics)
+	}
+
+	published = c.open("file:///formulas.txt", "# formulas\n∀a:b=b\n0=0\n")
+	expected = []Diagnostic{{
+		Range:    Range{Position{1, 0}, Position{1, 6}},
+		Severity: SeverityError,
+		Source:   "tnt",
+		Message:  "not well formed: /: a does not occur; a variable may only be quantified in a formula in which it is free",
+	}}
+	if !reflect.DeepEqual(published.Diagnostics, expected) {
+		t.Errorf("expected %+v but got %+v", expected, published.Diagnostics)
+	}
+
+	c.notify("textDocument/didClose", DidCloseTextDocumentParams{
+		TextDocument: TextDocumentIdentifier{URI: "file:///formulas.txt"},
+	})
+	if published := c.diagnostics(); len(published.Diagnostics) != 0 {
+		t.Errorf("expected diagnostics to be cleared but got %+v", published.Diagnostics)
+	}
+}
+
+func TestHover(t *testing.T) {
+	c := newClient(t)
+	defer c.close()
+	c.open("file:///formulas.txt", "  a=S0\n")
+
+	var hover Hover
+	if err := c.call("textDocument/hover", at("file:///formulas.txt", 0, 3), &hover); err != nil {
+		t.Fatal(err)
+	}
+	expected := Hover{
+		Contents: MarkupContent{
+			Kind:  "markdown",
+			Value: "`a=S0`\n\nfree variables: [a]\n\nGödel number: 262111123666",
+		},
+		Range: &Range{Position{0, 2}, Position{0, 6}},
+	}
+	if !reflect.DeepEqual(hover, expected) {
+		t.Errorf("expected %+v but got %+v", expected, hover)
+	}
+
+	var none *Hover
+	if err := c.call("textDocument/hover", at("file:///formulas.txt", 0, 0), &none); err != nil || none != nil {
+		t.Errorf("expected no hover but got %+v, %v", none, err)
+	}
+}
+
+func TestDefinition(t *testing.T) {
+	c := newClient(t)
+	defer c.close()
+	c.open("file:///proof.tnt", proof)
+
+	var location Location
+	if err := c.call("textDocument/definition", at("file:///proof.tnt", 1, 34), &location); err != nil {
+		t.Fatal(err)
+	}
+	expected := Location{
+		URI:   "file:///proof.tnt",
+		Range: Range{Position{0, 0}, Position{0, 27}},
+	}
+	if location != expected {
+		t.Errorf("expected %+v but got %+v", expected, location)
+	}
+
+	var none *Location
+	if err := c.call("textDocument/definition", at("file:///proof.tnt", 1, 5), &none); err != nil || none != nil {
+		t.Errorf("expected no definition but got %+v, %v", none, err)
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	c := newClient(t)
+	defer c.close()
+
+	var items []CompletionItem
+	if err := c.call("textDocument/completion", at("file:///proof.tnt", 0, 0), &items); err != nil {
+		t.Fatal(err)
+	}
+	labels := make(map[string]bool)
+	for _, item := range items {
+		labels[item.Label] = true
+	}
+	for _, expected := range []string{"axiom", "premise", "specification", "De Morgan", "induction"} {
+		if !labels[expected] {
+			t.Errorf("expected completion %q in %v", expected, items)
+		}
+	}
+}
+
+func TestParseError(t *testing.T) {
+	c := newClient(t)
+	if _, err := io.WriteString(c.conn.w, "Content-Length: 5\r\n\r\n{bad}"); err != nil {
+		t.Fatal(err)
+	}
+	header, err := c.conn.r.ReadMIMEHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	length, _ := strconv.Atoi(header.Get("Content-Length"))
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.conn.r.R, body); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(body), `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,`) {
+		t.Errorf("expected a parse error with a null ID but got %s", body)
+	}
+	if rerr := c.call("initialize", map[string]interface{}{}, nil); rerr != nil {
+		t.Errorf("expected the server to continue but got %s", rerr)
+	}
+	c.close()
+}
+
+func TestContentLength(t *testing.T) {
+	for _, length := range []string{"-1", "1000000000", "x"} {
+		header := "Content-Length: " + length + "\r\n\r\n{}"
+		err := Serve(strings.NewReader(header), io.Discard)
+		if err == nil || !strings.HasPrefix(err.Error(), "invalid Content-Length") {
+			t.Errorf("%s: expected invalid Content-Length but got %v", length, err)
+		}
+	}
+}
+
+func TestUTF16(t *testing.T) {
+	doc := newDocument("file:///formulas.txt", "0=0 # 😀 a\n")
+	for character, expected := range map[int]int{0: 0, 6: 6, 8: 7, 10: 9, 20: 9} {
+		if i := doc.index(Position{0, character}); i != expected {
+			t.Errorf("expected character %d at index %d but got %d", character, expected, i)
+		}
+		if expected < 9 && doc.character(0, expected) != character {
+			t.Errorf("expected index %d at character %d but got %d",
+				expected, character, doc.character(0, expected))
+		}
+	}
+}
@@ -2,24 +2,154 @@ package tnt
 
 import (
 	"fmt"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/jeremyhuiskamp/tnt/token"
 )
 
+// ParseError describes an unexpected Token encountered while parsing.
+type ParseError struct {
+	// Src is the complete input that was being parsed.
+	Src string
+	// Pos is the position of the unexpected Token in Src.
+	Pos token.Position
+	// Expected lists the Tokens that would have been accepted.
+	Expected []token.Token
+	// Got is the Token that was found instead, with its Literal content.
+	Got     token.Token
+	Literal string
+}
+
+func (e *ParseError) Error() string {
+	expected := make([]string, len(e.Expected))
+	for i, tok := range e.Expected {
+		expected[i] = describe(tok, "")
+	}
+
+	var list string
+	switch len(expected) {
+	case 0:
+	case 1:
+		list = expected[0]
+	default:
+		list = strings.Join(expected[:len(expected)-1], ", ") +
+			" or " + expected[len(expected)-1]
+	}
+
+	return fmt.Sprintf("%s: expected %s but got %s",
+		e.Pos, list, describe(e.Got, e.Literal))
+}
+
+// Excerpt returns the line of Src containing the error, followed by a
+// line underlining the unexpected Token with carets.
+func (e *ParseError) Excerpt() string {
+	lines := strings.Split(e.Src, "\n")
+	if e.Pos.Line < 1 || e.Pos.Line > len(lines) {
+		return ""
+	}
+	line := []rune(strings.TrimRight(lines[e.Pos.Line-1], "\r"))
+
+	// Reproduce tabs from the line so that the carets are aligned
+	// regardless of tab width.
+	var indent strings.Builder
+	for i := 0; i < e.Pos.Column-1 && i < len(line); i++ {
+		if line[i] == '\t' {
+			indent.WriteRune('\t')
+		} else {
+			indent.WriteRune(' ')
+		}
+	}
+
+	width := utf8.RuneCountInString(e.Literal)
+	if width < 1 {
+		width = 1
+	}
+
+	return string(line) + "\n" + indent.String() + strings.Repeat("^", width)
+}
+
+// describe returns a human-readable description of a Token.
+func describe(tok token.Token, literal string) string {
+	switch tok {
+	case token.ILLEGAL, token.VARIABLE:
+		if literal != "" {
+			return fmt.Sprintf("%s %q", tok, literal)
+		}
+	case token.SUCCESSOR:
+		return "S"
+	}
+	if symbol := tok.Symbol(token.UNICODE); symbol != "" {
+		return symbol
+	}
+	return tok.String()
+}
+
+// unexpected returns a ParseError for the Token most recently scanned
+// from s.
+func unexpected(s *token.Scanner, tok token.Token, literal string,
+	expected ...token.Token) error {
+	return &ParseError{
+		Pos:      s.Position(),
+		Expected: expected,
+		Got:      tok,
+		Literal:  literal,
+	}
+}
+
+// termStart lists the Tokens that can begin a Term.
+var termStart = []token.Token{
+	token.ZERO, token.SUCCESSOR, token.VARIABLE, token.OPEN_PAREN,
+}
+
+// formulaStart lists the Tokens that can begin a Formula.
+var formulaStart = append(termStart[:len(termStart):len(termStart)],
+	token.NEGATION, token.OPEN_ANGLE, token.FOR_ALL, token.THERE_EXISTS)
+
 // ParseFormula parses a complete TNT Formula.
+//
+// Errors caused by unexpected input are of type *ParseError.
 func ParseFormula(src string) (Formula, error) {
 	s := token.NewScanner(src)
 	formula, err := parseFormula(s)
+	if err == nil {
+		tok, val := s.Scan()
+		if tok != token.EOF {
+			err = unexpected(s, tok, val, token.EOF)
+		}
+	}
+
 	if err != nil {
+		if perr, ok := err.(*ParseError); ok {
+			perr.Src = src
+		}
 		return nil, err
 	}
 
-	tok, _ := s.Scan()
-	if tok != token.EOF {
-		return nil, fmt.Errorf("expected EOF but got %s", tok)
+	return formula, nil
+}
+
+// ParseTerm parses a complete TNT Term.
+//
+// Errors caused by unexpected input are of type *ParseError.
+func ParseTerm(src string) (Term, error) {
+	s := token.NewScanner(src)
+	term, err := parseTerm(s)
+	if err == nil {
+		tok, val := s.Scan()
+		if tok != token.EOF {
+			err = unexpected(s, tok, val, token.EOF)
+		}
 	}
 
-	return formula, nil
+	if err != nil {
+		if perr, ok := err.(*ParseError); ok {
+			perr.Src = src
+		}
+		return nil, err
+	}
+
+	return term, nil
 }
 
 // parseFormula parses a TNT Formula from a Scanner, but does not
@@ -35,8 +165,10 @@ func parseFormula(s *token.Scanner) (Formula, error) {
 		return parseQuantification(FOR_ALL, s)
 	case token.THERE_EXISTS:
 		return parseQuantification(THERE_EXISTS, s)
-	default:
+	case token.ZERO, token.SUCCESSOR, token.VARIABLE, token.OPEN_PAREN:
 		return parseAtom(tok, val, s)
+	default:
+		return nil, unexpected(s, tok, val, formulaStart...)
 	}
 }
 
@@ -46,9 +178,9 @@ func parseAtom(tok token.Token, val string, s *token.Scanner) (Formula, error) {
 		return nil, err
 	}
 
-	tok, _ = s.Scan()
+	tok, val = s.Scan()
 	if tok != token.EQUALS {
-		return nil, fmt.Errorf("expected = but got %s", tok)
+		return nil, unexpected(s, tok, val, token.EQUALS)
 	}
 
 	right, err := parseTerm(s)
@@ -76,6 +208,10 @@ func parseTermToken(tok token.Token, val string, s *token.Scanner) (Term, error)
 		switch term := term.(type) {
 		case Numeral:
 			return Numeral(len(val)) + term, nil
+		case Successor:
+			// Successors separated by whitespace, eg "S Sa".
+			term.Quantity += len(val)
+			return term, nil
 		default:
 			return Successor{
 				Quantity: len(val),
@@ -91,14 +227,14 @@ func parseTermToken(tok token.Token, val string, s *token.Scanner) (Term, error)
 		}
 
 		var kind CompoundTermKind
-		tok, _ := s.Scan()
+		tok, val := s.Scan()
 		switch tok {
 		case token.PLUS:
 			kind = PLUS
 		case token.MULTIPLY:
 			kind = MULTIPLY
 		default:
-			return nil, fmt.Errorf("expected + or * but got %s", tok)
+			return nil, unexpected(s, tok, val, token.PLUS, token.MULTIPLY)
 		}
 
 		right, err := parseTerm(s)
@@ -106,9 +242,9 @@ func parseTermToken(tok token.Token, val string, s *token.Scanner) (Term, error)
 			return nil, err
 		}
 
-		tok, _ = s.Scan()
+		tok, val = s.Scan()
 		if tok != token.CLOSE_PAREN {
-			return nil, fmt.Errorf("expected ) but got %s", tok)
+			return nil, unexpected(s, tok, val, token.CLOSE_PAREN)
 		}
 
 		return CompoundTerm{
@@ -117,7 +253,7 @@ func parseTermToken(tok token.Token, val string, s *token.Scanner) (Term, error)
 			Right: right,
 		}, nil
 	}
-	return nil, fmt.Errorf("unexpected token in term: %s", tok)
+	return nil, unexpected(s, tok, val, termStart...)
 }
 
 // parseTerm parses a Term from the Scanner assuming none of the
@@ -134,7 +270,7 @@ func parseCompound(s *token.Scanner) (Formula, error) {
 	}
 
 	var kind CompoundKind
-	tok, _ := s.Scan()
+	tok, val := s.Scan()
 	switch tok {
 	case token.AND:
 		kind = AND
@@ -143,8 +279,7 @@ func parseCompound(s *token.Scanner) (Formula, error) {
 	case token.IF_THEN:
 		kind = IF_THEN
 	default:
-		return nil, fmt.Errorf("expected AND, OR or IF_THEN in compound formula, "+
-			"but got %s", tok)
+		return nil, unexpected(s, tok, val, token.AND, token.OR, token.IF_THEN)
 	}
 
 	right, err := parseFormula(s)
@@ -152,9 +287,9 @@ func parseCompound(s *token.Scanner) (Formula, error) {
 		return nil, err
 	}
 
-	tok, _ = s.Scan()
+	tok, val = s.Scan()
 	if tok != token.CLOSE_ANGLE {
-		return nil, fmt.Errorf("expected > but got %s", tok)
+		return nil, unexpected(s, tok, val, token.CLOSE_ANGLE)
 	}
 
 	return Compound{
@@ -175,12 +310,12 @@ func parseNegation(s *token.Scanner) (Formula, error) {
 func parseQuantification(kind QuantificationKind, s *token.Scanner) (Formula, error) {
 	tok, varName := s.Scan()
 	if tok != token.VARIABLE {
-		return nil, fmt.Errorf("expected VARIABLE but got %s", tok)
+		return nil, unexpected(s, tok, varName, token.VARIABLE)
 	}
 
-	tok, _ = s.Scan()
+	tok, val := s.Scan()
 	if tok != token.COLON {
-		return nil, fmt.Errorf("expected : but got %s", tok)
+		return nil, unexpected(s, tok, val, token.COLON)
 	}
 
 	formula, err := parseFormula(s)
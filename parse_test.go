@@ -3,6 +3,8 @@ package tnt
 import (
 	"reflect"
 	"testing"
+
+	"github.com/jeremyhuiskamp/tnt/token"
 )
 
 func TestParseFormula(t *testing.T) {
@@ -140,32 +142,118 @@ func TestParseFormula(t *testing.T) {
 }
 
 func TestParseInvalidFormula(t *testing.T) {
-	// TODO: add assertions about the meaning of the error
-	// eg, position, expected token, actual token,
-	// and perhaps what we thought we were working on
-	for _, badFormula := range []string{
-		"",
-		"a",
-		"0=0 a",
-		"a+b=c+d",
-		"(a-b)=0",
-		"0=(a-b)",
-		"S(a-b)=0",
-		"((a-b)+c)=0",
-		"(a+(b-c))=0",
-		"(a+b=0)",
-		"<(a)^0=0>",
-		"<0=0_0=0>",
-		"<0=0^(a)>",
-		"<0=0^0=0 b>",
-		"~(a)=0",
-		"A0:S0=0",
-		"Aa_0=0",
-		"Aa:(a)=b",
+	type testCase struct {
+		Input    string
+		Column   int
+		Expected []token.Token
+		Got      token.Token
+	}
+
+	term := []token.Token{token.ZERO, token.SUCCESSOR, token.VARIABLE,
+		token.OPEN_PAREN}
+	formula := append(term, token.NEGATION, token.OPEN_ANGLE,
+		token.FOR_ALL, token.THERE_EXISTS)
+
+	for _, test := range []testCase{
+		{"", 1, formula, token.EOF},
+		{"a", 2, []token.Token{token.EQUALS}, token.EOF},
+		{"0=0 a", 5, []token.Token{token.EOF}, token.VARIABLE},
+		{"a+b=c+d", 2, []token.Token{token.EQUALS}, token.PLUS},
+		{"(a-b)=0", 3, []token.Token{token.PLUS, token.MULTIPLY}, token.ILLEGAL},
+		{"0=(a-b)", 5, []token.Token{token.PLUS, token.MULTIPLY}, token.ILLEGAL},
+		{"S(a-b)=0", 4, []token.Token{token.PLUS, token.MULTIPLY}, token.ILLEGAL},
+		{"((a-b)+c)=0", 4, []token.Token{token.PLUS, token.MULTIPLY}, token.ILLEGAL},
+		{"(a+(b-c))=0", 6, []token.Token{token.PLUS, token.MULTIPLY}, token.ILLEGAL},
+		{"(a+b=0)", 5, []token.Token{token.CLOSE_PAREN}, token.EQUALS},
+		{"<(a)^0=0>", 4, []token.Token{token.PLUS, token.MULTIPLY}, token.CLOSE_PAREN},
+		{"<0=0_0=0>", 5, []token.Token{token.AND, token.OR, token.IF_THEN}, token.ILLEGAL},
+		{"<0=0^(a)>", 8, []token.Token{token.PLUS, token.MULTIPLY}, token.CLOSE_PAREN},
+		{"<0=0^0=0 b>", 10, []token.Token{token.CLOSE_ANGLE}, token.VARIABLE},
+		{"~(a)=0", 4, []token.Token{token.PLUS, token.MULTIPLY}, token.CLOSE_PAREN},
+		{"A0:S0=0", 2, []token.Token{token.VARIABLE}, token.ZERO},
+		{"Aa_0=0", 3, []token.Token{token.COLON}, token.ILLEGAL},
+		{"Aa:(a)=b", 6, []token.Token{token.PLUS, token.MULTIPLY}, token.CLOSE_PAREN},
+		{"0=>", 3, term, token.CLOSE_ANGLE},
+		{"<0=0∧>", 6, formula, token.CLOSE_ANGLE},
 	} {
-		_, err := ParseFormula(badFormula)
+		_, err := ParseFormula(test.Input)
 		if err == nil {
-			t.Fatalf("expected error for formula: %q", badFormula)
+			t.Errorf("expected error for formula: %q", test.Input)
+			continue
+		}
+		perr, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("expected *ParseError for %q but got %T", test.Input, err)
+			continue
+		}
+		if perr.Src != test.Input {
+			t.Errorf("%q: expected error source to be the input, but got %q",
+				test.Input, perr.Src)
+		}
+		if perr.Pos.Line != 1 || perr.Pos.Column != test.Column {
+			t.Errorf("%q: expected error at 1:%d but got %s",
+				test.Input, test.Column, perr.Pos)
+		}
+		if !reflect.DeepEqual(perr.Expected, test.Expected) {
+			t.Errorf("%q: expected tokens %v but got %v",
+				test.Input, test.Expected, perr.Expected)
+		}
+		if perr.Got != test.Got {
+			t.Errorf("%q: expected unexpected token %s but got %s",
+				test.Input, test.Got, perr.Got)
+		}
+	}
+}
+
+func TestParseErrorMessage(t *testing.T) {
+	_, err := ParseFormula("∀a:\n\t<a=0 ∧ a=a_0>")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	perr := err.(*ParseError)
+
+	expectedMsg := `2:12: expected > but got ILLEGAL "_"`
+	if perr.Error() != expectedMsg {
+		t.Errorf("expected message %q but got %q", expectedMsg, perr.Error())
+	}
+
+	expectedExcerpt := "\t<a=0 ∧ a=a_0>\n\t          ^"
+	if perr.Excerpt() != expectedExcerpt {
+		t.Errorf("expected excerpt:\n%s\nbut got:\n%s",
+			expectedExcerpt, perr.Excerpt())
+	}
+
+	_, err = ParseFormula("(a+b)=(b+a) a'' ")
+	perr = err.(*ParseError)
+	expectedExcerpt = "(a+b)=(b+a) a'' \n            ^^^"
+	if perr.Excerpt() != expectedExcerpt {
+		t.Errorf("expected excerpt:\n%s\nbut got:\n%s",
+			expectedExcerpt, perr.Excerpt())
+	}
+}
+
+func TestParseTerm(t *testing.T) {
+	term, err := ParseTerm("S S(a·SS0)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Successor{
+		Quantity: 2,
+		Term: CompoundTerm{
+			Kind:  MULTIPLY,
+			Left:  Variable("a"),
+			Right: Numeral(2),
+		},
+	}
+	if !reflect.DeepEqual(term, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, term)
+	}
+
+	for _, bad := range []string{"", "a=b", "(a+b", "~a"} {
+		if _, err := ParseTerm(bad); err == nil {
+			t.Errorf("expected error for term: %q", bad)
+		} else if _, ok := err.(*ParseError); !ok {
+			t.Errorf("expected *ParseError for %q but got %T", bad, err)
 		}
 	}
 }
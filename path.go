@@ -0,0 +1,112 @@
+package tnt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Branch selects one of the parts of a Term or Formula.
+type Branch int
+
+//go:generate stringer -type Branch
+
+const (
+	// LEFT is the left side of an Atom, Compound or CompoundTerm.
+	LEFT Branch = iota
+	// RIGHT is the right side of an Atom, Compound or CompoundTerm.
+	RIGHT
+	// BODY is the Formula of a Negation or Quantification, or the Term
+	// of a Successor.
+	BODY
+)
+
+// Path locates a part of a Formula as the sequence of Branches leading
+// to it from the top.  The empty Path is the Formula itself.
+type Path []Branch
+
+// String returns the Path in the form /LEFT/BODY, or / if the Path is
+// empty.
+func (p Path) String() string {
+	if len(p) == 0 {
+		return "/"
+	}
+	var b strings.Builder
+	for _, branch := range p {
+		b.WriteString("/")
+		b.WriteString(branch.String())
+	}
+	return b.String()
+}
+
+// Child returns a new Path extending p with one more Branch.
+func (p Path) Child(b Branch) Path {
+	child := make(Path, len(p), len(p)+1)
+	copy(child, p)
+	return append(child, b)
+}
+
+// At returns the part of n located by p.
+func At(n Node, p Path) (Node, error) {
+	for i, b := range p {
+		c := child(n, b)
+		if c == nil {
+			return nil, fmt.Errorf("no part at %s: %s has no %s", p, p[:i], b)
+		}
+		n = c
+	}
+	return n, nil
+}
+
+// ReplaceAt returns a copy of f in which the part located by p is
+// replaced by g.  g must be a Term if the part is a Term, and a Formula
+// if the part is a Formula.  f itself is not modified.
+func ReplaceAt(f Formula, p Path, g Node) (Formula, error) {
+	if g == nil {
+		return nil, fmt.Errorf("cannot replace part at %s with nothing", p)
+	}
+	replaced, err := replaceAt(f, p, g)
+	if err != nil {
+		return nil, fmt.Errorf("cannot replace part at %s: %s", p, err)
+	}
+	formula, ok := replaced.(Formula)
+	if !ok || isTerm(replaced) {
+		return nil, fmt.Errorf("cannot replace formula %s with term %s", f, g)
+	}
+	return formula, nil
+}
+
+func replaceAt(n Node, p Path, g Node) (Node, error) {
+	if len(p) == 0 {
+		return g, nil
+	}
+	c := child(n, p[0])
+	if c == nil {
+		return nil, fmt.Errorf("%s has no %s", n, p[0])
+	}
+	replaced, err := replaceAt(c, p[1:], g)
+	if err != nil {
+		return nil, err
+	}
+	return withChild(n, p[0], replaced)
+}
+
+// Part is a Term or Formula inside another, along with its location.
+type Part struct {
+	Path Path
+	Node Node
+}
+
+// Parts returns n and every Term and Formula inside it, in depth-first
+// order from left to right.
+func Parts(n Node) []Part {
+	var parts []Part
+	var visit func(n Node, p Path)
+	visit = func(n Node, p Path) {
+		parts = append(parts, Part{p, n})
+		for _, b := range branches(n) {
+			visit(child(n, b), p.Child(b))
+		}
+	}
+	visit(n, Path{})
+	return parts
+}
@@ -0,0 +1,141 @@
+package tnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPathString(t *testing.T) {
+	for expected, path := range map[string]Path{
+		"/":           {},
+		"/LEFT":       {LEFT},
+		"/BODY/RIGHT": {BODY, RIGHT},
+	} {
+		if got := path.String(); got != expected {
+			t.Errorf("expected %q but got %q", expected, got)
+		}
+	}
+}
+
+func TestAt(t *testing.T) {
+	formula, err := ParseFormula("∀a:<~a=S0∧(a+b)=0>")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		Path     Path
+		Expected string
+	}{
+		{Path{}, "∀a:<~a=S0∧(a+b)=0>"},
+		{Path{BODY}, "<~a=S0∧(a+b)=0>"},
+		{Path{BODY, LEFT}, "~a=S0"},
+		{Path{BODY, LEFT, BODY, RIGHT}, "S0"},
+		{Path{BODY, RIGHT, LEFT, RIGHT}, "b"},
+	} {
+		got, err := At(formula, test.Path)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.Path, err)
+		} else if got.String() != test.Expected {
+			t.Errorf("%s: expected %q but got %q",
+				test.Path, test.Expected, got)
+		}
+	}
+
+	for _, bad := range []Path{
+		{LEFT},
+		{BODY, BODY},
+		{BODY, RIGHT, LEFT, RIGHT, LEFT},
+	} {
+		if got, err := At(formula, bad); err == nil {
+			t.Errorf("%s: expected error but got %s", bad, got)
+		}
+	}
+}
+
+// otherNode is a Node that is neither a Term nor a Formula of this
+// package.
+type otherNode struct{}
+
+func (otherNode) Variables() VariableSet { return nil }
+func (otherNode) String() string         { return "?" }
+
+func TestReplaceAt(t *testing.T) {
+	input := "∀a:<~a=S0∧(a+b)=0>"
+	formula, err := ParseFormula(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		Path     Path
+		Node     Node
+		Expected string
+	}{
+		{Path{}, Atom{Numeral(0), Numeral(0)}, "0=0"},
+		{Path{BODY, LEFT}, Negation{Negation{Atom{Variable("a"), Numeral(1)}}},
+			"∀a:<~~a=S0∧(a+b)=0>"},
+		{Path{BODY, RIGHT, LEFT, RIGHT}, Numeral(2), "∀a:<~a=S0∧(a+SS0)=0>"},
+		{Path{BODY, LEFT, BODY, RIGHT}, Successor{1, Variable("a")}, "∀a:<~a=Sa∧(a+b)=0>"},
+	} {
+		got, err := ReplaceAt(formula, test.Path, test.Node)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.Path, err)
+		} else if got.String() != test.Expected {
+			t.Errorf("%s: expected %q but got %q",
+				test.Path, test.Expected, got)
+		}
+	}
+
+	for _, test := range []struct {
+		Path Path
+		Node Node
+	}{
+		{Path{}, Numeral(0)},
+		{Path{BODY}, Variable("a")},
+		{Path{BODY, RIGHT, LEFT}, Atom{Numeral(0), Numeral(0)}},
+		{Path{RIGHT}, Atom{Numeral(0), Numeral(0)}},
+		{Path{}, nil},
+		{Path{BODY, LEFT}, nil},
+		{Path{}, otherNode{}},
+		{Path{BODY, LEFT, BODY}, otherNode{}},
+		{Path{BODY, RIGHT, LEFT, RIGHT}, otherNode{}},
+	} {
+		if got, err := ReplaceAt(formula, test.Path, test.Node); err == nil {
+			t.Errorf("%s: expected error but got %s", test.Path, got)
+		}
+	}
+
+	if got := formula.String(); got != input {
+		t.Errorf("expected original to be unmodified, but got %q", got)
+	}
+}
+
+func TestParts(t *testing.T) {
+	formula, err := ParseFormula("~Sa=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, part := range Parts(formula) {
+		got = append(got, part.Path.String()+" "+part.Node.String())
+
+		at, err := At(formula, part.Path)
+		if err != nil || !reflect.DeepEqual(at, part.Node) {
+			t.Errorf("expected %s to be at %s, but got %v (%v)",
+				part.Node, part.Path, at, err)
+		}
+	}
+
+	expected := []string{
+		"/ ~Sa=0",
+		"/BODY Sa=0",
+		"/BODY/LEFT Sa",
+		"/BODY/LEFT/BODY a",
+		"/BODY/RIGHT 0",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected parts %q but got %q", expected, got)
+	}
+}
@@ -0,0 +1,347 @@
+// Package playground serves a web page for experimenting with TNT, and
+// the JSON API behind it.
+//
+// The API accepts POST requests with JSON bodies:
+//
+//	/api/parse  {"formula": "∀a:a=a"}
+//	/api/eval   {"formula": "∃b:a=Sb", "bound": 10, "env": {"a": "3"}}
+//	/api/check  {"derivation": "1  ∀a:(a+0)=a  (axiom 2)"}
+//
+// Invalid input is answered with status 400 and an Error.
+package playground
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/jeremyhuiskamp/tnt"
+	"github.com/jeremyhuiskamp/tnt/derivation"
+)
+
+//go:embed index.html
+var index []byte
+
+// MaxBound is the largest bound accepted by /api/eval.
+const MaxBound = 100
+
+// MaxEvaluations limits the work of /api/eval.  A formula of n parts
+// with k nested quantifiers may take n·(bound+1)^k evaluations of its
+// parts, which must not exceed MaxEvaluations.
+const MaxEvaluations = 1000000
+
+// maxBody is the largest request body accepted.
+const maxBody = 1 << 20
+
+// New returns a handler for the playground page and its API.  Steps of
+// checked derivations may state axioms from the given set, or from the
+// axioms of TNT if it is nil.
+func New(axioms tnt.AxiomSet) http.Handler {
+	if axioms == nil {
+		axioms = tnt.Axioms
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(index)
+	})
+	mux.HandleFunc("/api/parse", api(parse))
+	mux.HandleFunc("/api/eval", api(eval))
+	mux.HandleFunc("/api/check", api(func(ctx context.Context, decode func(interface{}) error) (interface{}, error) {
+		return check(ctx, decode, axioms)
+	}))
+	return mux
+}
+
+// Error is the response to invalid input.
+type Error struct {
+	Message string `json:"error"`
+	// Line and Column locate the error in the input, if known.
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+	// Excerpt shows the line of the error with the position marked.
+	Excerpt string `json:"excerpt,omitempty"`
+}
+
+// newError describes err, locating parse errors.
+func newError(err error) *Error {
+	e := &Error{Message: err.Error()}
+	var perr *tnt.ParseError
+	var derr *derivation.Error
+	switch {
+	case errors.As(err, &perr):
+		e.Line, e.Column, e.Excerpt = perr.Pos.Line, perr.Pos.Column, perr.Excerpt()
+	case errors.As(err, &derr):
+		e.Line, e.Column = derr.Pos.Line, derr.Pos.Column
+	}
+	return e
+}
+
+// api adapts a function that decodes a request and returns a response
+// into a handler for JSON POST requests.  The function is given the
+// context of the request.
+func api(f func(ctx context.Context, decode func(interface{}) error) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSON(w, http.StatusMethodNotAllowed, &Error{Message: "method not allowed"})
+			return
+		}
+
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBody))
+		dec.DisallowUnknownFields()
+		decode := func(req interface{}) error {
+			if err := dec.Decode(req); err != nil {
+				return fmt.Errorf("invalid request: %s", err)
+			}
+			return nil
+		}
+
+		resp, err := f(r.Context(), decode)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, newError(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+type parseRequest struct {
+	Formula string `json:"formula"`
+}
+
+// Node is a node of a syntax tree.
+type Node struct {
+	// Label describes the node without its children.
+	Label    string `json:"label"`
+	Text     string `json:"text"`
+	Children []Node `json:"children,omitempty"`
+}
+
+type parseResponse struct {
+	Formula       string   `json:"formula"`
+	Tree          Node     `json:"tree"`
+	Variables     []string `json:"variables"`
+	FreeVariables []string `json:"freeVariables"`
+	Open          bool     `json:"open"`
+	WellFormed    bool     `json:"wellFormed"`
+	// Diagnostics explain why the formula is not well formed.
+	Diagnostics []string `json:"diagnostics"`
+}
+
+func parse(_ context.Context, decode func(interface{}) error) (interface{}, error) {
+	var req parseRequest
+	if err := decode(&req); err != nil {
+		return nil, err
+	}
+	f, err := tnt.ParseFormula(req.Formula)
+	if err != nil {
+		return nil, err
+	}
+	resp := parseResponse{
+		Formula:       f.String(),
+		Tree:          tree(f),
+		Variables:     names(f.Variables()),
+		FreeVariables: names(f.FreeVariables()),
+		Open:          f.Open(),
+		WellFormed:    f.WellFormed(),
+		Diagnostics:   []string{},
+	}
+	for _, d := range tnt.CheckWellFormed(f) {
+		resp.Diagnostics = append(resp.Diagnostics, d.String())
+	}
+	return resp, nil
+}
+
+// tree returns the syntax tree of n.
+func tree(n tnt.Node) Node {
+	node := Node{Text: n.String()}
+	switch n := n.(type) {
+	case tnt.Numeral:
+		node.Label = fmt.Sprintf("Numeral %d", int(n))
+	case tnt.Variable:
+		node.Label = "Variable"
+	case tnt.Successor:
+		node.Label = fmt.Sprintf("Successor %d", n.Quantity)
+		node.Children = []Node{tree(n.Term)}
+	case tnt.CompoundTerm:
+		node.Label = "CompoundTerm " + n.Kind.String()
+		node.Children = []Node{tree(n.Left), tree(n.Right)}
+	case tnt.Atom:
+		node.Label = "Atom"
+		node.Children = []Node{tree(n.Left), tree(n.Right)}
+	case tnt.Negation:
+		node.Label = "Negation"
+		node.Children = []Node{tree(n.Formula)}
+	case tnt.Compound:
+		node.Label = "Compound " + n.Kind.String()
+		node.Children = []Node{tree(n.Left), tree(n.Right)}
+	case tnt.Quantification:
+		node.Label = fmt.Sprintf("Quantification %s %s", n.Kind, n.Variable)
+		node.Children = []Node{tree(n.Formula)}
+	}
+	return node
+}
+
+func names(vs tnt.VariableSet) []string {
+	names := make([]string, 0, len(vs))
+	for v := range vs {
+		names = append(names, string(v))
+	}
+	sort.Strings(names)
+	return names
+}
+
+type evalRequest struct {
+	Formula string `json:"formula"`
+	Bound   uint64 `json:"bound"`
+	// Env gives the values of the free variables, in decimal.
+	Env map[string]string `json:"env"`
+}
+
+type evalResponse struct {
+	Value bool `json:"value"`
+	// Assignment gives the values of the quantified variables that
+	// decided the value, in decimal.
+	Assignment map[string]string `json:"assignment"`
+}
+
+func eval(ctx context.Context, decode func(interface{}) error) (interface{}, error) {
+	var req evalRequest
+	if err := decode(&req); err != nil {
+		return nil, err
+	}
+	if req.Bound > MaxBound {
+		return nil, fmt.Errorf("bound %d is larger than %d", req.Bound, MaxBound)
+	}
+	f, err := tnt.ParseFormula(req.Formula)
+	if err != nil {
+		return nil, err
+	}
+	depth, size := nesting(f), len(tnt.Parts(f))
+	if !withinBudget(req.Bound, depth, size) {
+		return nil, fmt.Errorf("bound %d with %d nested quantifiers over %d parts needs more than %d evaluations",
+			req.Bound, depth, size, MaxEvaluations)
+	}
+
+	env := make(map[tnt.Variable]*big.Int, len(req.Env))
+	for name, value := range req.Env {
+		n, ok := new(big.Int).SetString(value, 10)
+		if !ok || n.Sign() < 0 {
+			return nil, fmt.Errorf("value %q of %s is not a natural number", value, name)
+		}
+		env[tnt.Variable(name)] = n
+	}
+
+	value, assignment, err := tnt.EvalBoundedContext(ctx, f, env, req.Bound)
+	if err != nil {
+		return nil, err
+	}
+	resp := evalResponse{
+		Value:      value,
+		Assignment: make(map[string]string, len(assignment)),
+	}
+	for v, n := range assignment {
+		resp.Assignment[string(v)] = n.String()
+	}
+	return resp, nil
+}
+
+// nesting returns the greatest number of quantifiers nested in f.
+func nesting(f tnt.Formula) int {
+	switch f := f.(type) {
+	case tnt.Negation:
+		return nesting(f.Formula)
+	case tnt.Compound:
+		left, right := nesting(f.Left), nesting(f.Right)
+		if left > right {
+			return left
+		}
+		return right
+	case tnt.Quantification:
+		return 1 + nesting(f.Formula)
+	}
+	return 0
+}
+
+// withinBudget reports whether size·(bound+1)^depth is at most
+// MaxEvaluations.
+func withinBudget(bound uint64, depth, size int) bool {
+	evaluations := uint64(size)
+	if evaluations > MaxEvaluations {
+		return false
+	}
+	for i := 0; i < depth; i++ {
+		evaluations *= bound + 1
+		if evaluations > MaxEvaluations {
+			return false
+		}
+	}
+	return true
+}
+
+type checkRequest struct {
+	Derivation string `json:"derivation"`
+}
+
+// Step is a step of a checked derivation.
+type Step struct {
+	Formula  string `json:"formula,omitempty"`
+	Rule     string `json:"rule"`
+	Premises []int  `json:"premises,omitempty"`
+}
+
+type checkResponse struct {
+	Valid bool   `json:"valid"`
+	Steps []Step `json:"steps"`
+	// Step is the number of the first invalid step, and Error explains
+	// why it is invalid.
+	Step  int    `json:"step,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+func check(_ context.Context, decode func(interface{}) error, axioms tnt.AxiomSet) (interface{}, error) {
+	var req checkRequest
+	if err := decode(&req); err != nil {
+		return nil, err
+	}
+	d, err := derivation.Parse(strings.NewReader(req.Derivation))
+	if err != nil {
+		return nil, err
+	}
+	d.Axioms = axioms
+
+	resp := checkResponse{Valid: true, Steps: make([]Step, len(d.Steps))}
+	for i, step := range d.Steps {
+		resp.Steps[i] = Step{Rule: step.Rule, Premises: step.Premises}
+		if step.Formula != nil {
+			resp.Steps[i].Formula = step.Formula.String()
+		}
+	}
+
+	var stepErr *tnt.StepError
+	if err := tnt.Check(d); errors.As(err, &stepErr) {
+		resp.Valid = false
+		resp.Step = stepErr.Step
+		resp.Error = stepErr.Err.Error()
+	} else if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
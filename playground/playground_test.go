@@ -0,0 +1,171 @@
+package playground
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// post sends a JSON request to the handler and decodes the response
+// into resp, returning the status.
+func post(t *testing.T, h http.Handler, path, body string, resp interface{}) int {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("%s: expected JSON but got %s", path, ct)
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), resp); err != nil {
+		t.Fatalf("%s: %s: %s", path, err, rec.Body)
+	}
+	return rec.Code
+}
+
+func TestPage(t *testing.T) {
+	h := New(nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "/api/parse") {
+		t.Errorf("expected the playground page but got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected not found but got %d", rec.Code)
+	}
+}
+
+func TestParse(t *testing.T) {
+	h := New(nil)
+
+	var resp parseResponse
+	status := post(t, h, "/api/parse", `{"formula": "Ab:<a=b V ~SS0=b>"}`, &resp)
+	expected := parseResponse{
+		Formula: "∀b:<a=b∨~SS0=b>",
+		Tree: Node{Label: "Quantification FOR_ALL b", Text: "∀b:<a=b∨~SS0=b>", Children: []Node{
+			{Label: "Compound OR", Text: "<a=b∨~SS0=b>", Children: []Node{
+				{Label: "Atom", Text: "a=b", Children: []Node{
+					{Label: "Variable", Text: "a"},
+					{Label: "Variable", Text: "b"},
+				}},
+				{Label: "Negation", Text: "~SS0=b", Children: []Node{
+					{Label: "Atom", Text: "SS0=b", Children: []Node{
+						{Label: "Numeral 2", Text: "SS0"},
+						{Label: "Variable", Text: "b"},
+					}},
+				}},
+			}},
+		}},
+		Variables:     []string{"a", "b"},
+		FreeVariables: []string{"a"},
+		Open:          true,
+		WellFormed:    true,
+		Diagnostics:   []string{},
+	}
+	if status != http.StatusOK || !reflect.DeepEqual(resp, expected) {
+		t.Errorf("expected %d %+v but got %d %+v", http.StatusOK, expected, status, resp)
+	}
+
+	var perr Error
+	status = post(t, h, "/api/parse", `{"formula": "a=_"}`, &perr)
+	expectedErr := Error{
+		Message: `1:3: expected 0, S, VARIABLE or ( but got ILLEGAL "_"`,
+		Line:    1,
+		Column:  3,
+		Excerpt: "a=_\n  ^",
+	}
+	if status != http.StatusBadRequest || perr != expectedErr {
+		t.Errorf("expected %+v but got %d %+v", expectedErr, status, perr)
+	}
+}
+
+func TestEval(t *testing.T) {
+	h := New(nil)
+
+	var resp evalResponse
+	status := post(t, h, "/api/eval", `{"formula": "∀b:~a=(b+b)", "bound": 5, "env": {"a": "4"}}`, &resp)
+	expected := evalResponse{Value: false, Assignment: map[string]string{"b": "2"}}
+	if status != http.StatusOK || !reflect.DeepEqual(resp, expected) {
+		t.Errorf("expected %+v but got %d %+v", expected, status, resp)
+	}
+
+	for body, message := range map[string]string{
+		`{"formula": "a=0", "bound": 5}`:                            "variable a is unbound",
+		`{"formula": "a=0", "env": {"a": "-1"}}`:                    `value "-1" of a is not a natural number`,
+		`{"formula": "∃a:a=0", "bound": 1000}`:                      "bound 1000 is larger than 100",
+		`{"formula": "0=0", "unknown": true}`:                       `invalid request: json: unknown field "unknown"`,
+		`{"formula": "∀a:<∃b:a=b∧∀c:∀d:∀e:(c+d)=e>", "bound": 100}`: "bound 100 with 4 nested quantifiers over 14 parts needs more than 1000000 evaluations",
+	} {
+		var perr Error
+		if status := post(t, h, "/api/eval", body, &perr); status != http.StatusBadRequest ||
+			perr.Message != message {
+			t.Errorf("%s: expected error %q but got %d %q", body, message, status, perr.Message)
+		}
+	}
+
+	// Few quantifiers, but a long formula to evaluate for each value.
+	long := "∀a:∀b:" + strings.Repeat("<(a+b)=(b+a)∧", 39) + "(a+b)=(b+a)" + strings.Repeat(">", 39)
+	var perr Error
+	message := "bound 100 with 2 nested quantifiers over 321 parts needs more than 1000000 evaluations"
+	if status := post(t, h, "/api/eval", `{"formula": "`+long+`", "bound": 100}`, &perr); status != http.StatusBadRequest ||
+		perr.Message != message {
+		t.Errorf("expected error %q but got %d %q", message, status, perr.Message)
+	}
+}
+
+func TestEvalCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	decode := func(req interface{}) error {
+		return json.Unmarshal([]byte(`{"formula": "∀a:∀b:~a=SSb", "bound": 10}`), req)
+	}
+	if _, err := eval(ctx, decode); err != context.Canceled {
+		t.Errorf("expected evaluation to be canceled but got %v", err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	h := New(nil)
+	derivation := "1  ∀a:(a+0)=a  (axiom 2)\n2  (S0+0)=S0  (specification: 1)\n"
+
+	var resp checkResponse
+	body, _ := json.Marshal(checkRequest{Derivation: derivation})
+	status := post(t, h, "/api/check", string(body), &resp)
+	expected := checkResponse{
+		Valid: true,
+		Steps: []Step{
+			{Formula: "∀a:(a+0)=a", Rule: "axiom 2"},
+			{Formula: "(S0+0)=S0", Rule: "specification", Premises: []int{1}},
+		},
+	}
+	if status != http.StatusOK || !reflect.DeepEqual(resp, expected) {
+		t.Errorf("expected %+v but got %d %+v", expected, status, resp)
+	}
+
+	resp = checkResponse{}
+	body, _ = json.Marshal(checkRequest{Derivation: strings.Replace(derivation, "axiom 2", "axiom 1", 1)})
+	post(t, h, "/api/check", string(body), &resp)
+	if resp.Valid || resp.Step != 1 || resp.Error != "axiom 1: expected ∀a:~Sa=0 but got ∀a:(a+0)=a" {
+		t.Errorf("expected step 1 to be invalid but got %+v", resp)
+	}
+
+	var perr Error
+	status = post(t, h, "/api/check", `{"derivation": "2  0=0  (axiom)"}`, &perr)
+	if status != http.StatusBadRequest || perr.Message != "1:1: expected step 1 but got 2" || perr.Line != 1 {
+		t.Errorf("expected layout error but got %d %+v", status, perr)
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	rec := httptest.NewRecorder()
+	New(nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/parse", nil))
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != http.MethodPost {
+		t.Errorf("expected method not allowed but got %d", rec.Code)
+	}
+}
@@ -0,0 +1,96 @@
+package tnt
+
+import (
+	"strings"
+
+	"github.com/jeremyhuiskamp/tnt/token"
+)
+
+// Printer writes Terms and Formulas in a chosen Dialect.  The zero
+// value prints the Unicode notation used in the book.
+//
+// All Dialects can be read back by ParseFormula.
+type Printer struct {
+	Dialect token.Dialect
+}
+
+// Term returns the Term written in the Printer's Dialect.
+func (p Printer) Term(t Term) string {
+	var b strings.Builder
+	p.term(&b, t)
+	return b.String()
+}
+
+// Formula returns the Formula written in the Printer's Dialect.
+func (p Printer) Formula(f Formula) string {
+	var b strings.Builder
+	p.formula(&b, f)
+	return b.String()
+}
+
+func (p Printer) symbol(b *strings.Builder, tok token.Token) {
+	b.WriteString(tok.Symbol(p.Dialect))
+}
+
+func (p Printer) term(b *strings.Builder, t Term) {
+	switch t := t.(type) {
+	case Numeral:
+		for i := 0; i < int(t); i++ {
+			p.symbol(b, token.SUCCESSOR)
+		}
+		p.symbol(b, token.ZERO)
+	case Variable:
+		b.WriteString(string(t))
+	case Successor:
+		for i := 0; i < t.Quantity; i++ {
+			p.symbol(b, token.SUCCESSOR)
+		}
+		p.term(b, t.Term)
+	case CompoundTerm:
+		p.symbol(b, token.OPEN_PAREN)
+		p.term(b, t.Left)
+		switch t.Kind {
+		case PLUS:
+			p.symbol(b, token.PLUS)
+		case MULTIPLY:
+			p.symbol(b, token.MULTIPLY)
+		}
+		p.term(b, t.Right)
+		p.symbol(b, token.CLOSE_PAREN)
+	}
+}
+
+func (p Printer) formula(b *strings.Builder, f Formula) {
+	switch f := f.(type) {
+	case Atom:
+		p.term(b, f.Left)
+		p.symbol(b, token.EQUALS)
+		p.term(b, f.Right)
+	case Negation:
+		p.symbol(b, token.NEGATION)
+		p.formula(b, f.Formula)
+	case Compound:
+		p.symbol(b, token.OPEN_ANGLE)
+		p.formula(b, f.Left)
+		switch f.Kind {
+		case AND:
+			p.symbol(b, token.AND)
+		case OR:
+			p.symbol(b, token.OR)
+		case IF_THEN:
+			p.symbol(b, token.IF_THEN)
+		}
+		p.formula(b, f.Right)
+		p.symbol(b, token.CLOSE_ANGLE)
+	case Quantification:
+		switch f.Kind {
+		case THERE_EXISTS:
+			p.symbol(b, token.THERE_EXISTS)
+		case FOR_ALL:
+			p.symbol(b, token.FOR_ALL)
+		}
+		b.WriteString(string(f.Variable))
+		p.symbol(b, token.COLON)
+		p.formula(b, f.Formula)
+	}
+}
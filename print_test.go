@@ -0,0 +1,59 @@
+package tnt
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jeremyhuiskamp/tnt/token"
+)
+
+func TestPrinter(t *testing.T) {
+	type testCase struct {
+		Input   string
+		Unicode string
+		ASCII   string
+	}
+
+	for _, test := range []testCase{
+		{
+			Input:   "SS0=(a*Sb)",
+			Unicode: "SS0=(a·Sb)",
+			ASCII:   "SS0=(a*Sb)",
+		},
+		{
+			Input:   "∀a:∃b:<a=b∧~b=a>",
+			Unicode: "∀a:∃b:<a=b∧~b=a>",
+			ASCII:   "Aa:Eb:<a=b^~b=a>",
+		},
+		{
+			Input:   "<<a=0 V b=0> ⊃ <b=0 ∨ a=0>>",
+			Unicode: "<<a=0∨b=0>⊃<b=0∨a=0>>",
+			ASCII:   "<<a=0Vb=0>-><b=0Va=0>>",
+		},
+	} {
+		formula, err := ParseFormula(test.Input)
+		if err != nil {
+			t.Errorf("error parsing %q: %s", test.Input, err)
+			continue
+		}
+
+		for dialect, expected := range map[token.Dialect]string{
+			token.UNICODE: test.Unicode,
+			token.ASCII:   test.ASCII,
+		} {
+			got := Printer{Dialect: dialect}.Formula(formula)
+			if got != expected {
+				t.Errorf("dialect %d: expected %q to print as %q, but got %q",
+					dialect, test.Input, expected, got)
+			}
+
+			reparsed, err := ParseFormula(got)
+			if err != nil {
+				t.Errorf("error reparsing %q: %s", got, err)
+			} else if !reflect.DeepEqual(formula, reparsed) {
+				t.Errorf("expected %q to round-trip to %+v, but got %+v",
+					got, formula, reparsed)
+			}
+		}
+	}
+}
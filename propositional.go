@@ -0,0 +1,273 @@
+package tnt
+
+import "fmt"
+
+// This file implements the rules of the propositional calculus from
+// Chapter 7.  Each rule has a function that applies it, and a Rule,
+// registered under the rule's name in the book, that verifies it.
+
+func init() {
+	RegisterRule("joining", joining)
+	RegisterRule("separation", separation)
+	RegisterRule("double-tilde", doubleTilde)
+	RegisterRule("detachment", detachment)
+	RegisterRule("contrapositive", interchangeable(Contrapositive,
+		"<x⊃y> and <~y⊃~x>"))
+	RegisterRule("De Morgan", interchangeable(DeMorgan, "<~x∧~y> and ~<x∨y>"))
+	RegisterRule("switcheroo", interchangeable(Switcheroo, "<x∨y> and <~x⊃y>"))
+}
+
+// expectPremises returns an error if there are not n premises.
+func expectPremises(premises []Formula, n int) error {
+	if len(premises) != n {
+		plural := "s"
+		if n == 1 {
+			plural = ""
+		}
+		return fmt.Errorf("expected %d premise%s but got %d",
+			n, plural, len(premises))
+	}
+	return nil
+}
+
+// expectConclusion returns an error if conclusion is not expected.
+func expectConclusion(conclusion, expected Formula) error {
+	if !Equal(conclusion, expected) {
+		return fmt.Errorf("expected %s but got %s", expected, conclusion)
+	}
+	return nil
+}
+
+// Join applies the rule of joining: if x and y are theorems, then so is
+// <x∧y>.
+func Join(x, y Formula) Formula {
+	return Compound{
+		Kind:  AND,
+		Left:  x,
+		Right: y,
+	}
+}
+
+func joining(_ Context, premises []Formula, conclusion Formula) error {
+	if err := expectPremises(premises, 2); err != nil {
+		return err
+	}
+	if Equal(conclusion, Join(premises[1], premises[0])) {
+		return nil
+	}
+	return expectConclusion(conclusion, Join(premises[0], premises[1]))
+}
+
+// Separate applies the rule of separation: if <x∧y> is a theorem, then
+// so are x and y.
+func Separate(f Formula) (x, y Formula, err error) {
+	c, ok := f.(Compound)
+	if !ok || c.Kind != AND {
+		return nil, nil, fmt.Errorf("%s is not of the form <x∧y>", f)
+	}
+	return c.Left, c.Right, nil
+}
+
+func separation(_ Context, premises []Formula, conclusion Formula) error {
+	if err := expectPremises(premises, 1); err != nil {
+		return err
+	}
+	x, y, err := Separate(premises[0])
+	if err != nil {
+		return err
+	}
+	if !Equal(conclusion, x) && !Equal(conclusion, y) {
+		return fmt.Errorf("expected %s or %s but got %s", x, y, conclusion)
+	}
+	return nil
+}
+
+// DoubleTilde applies the double-tilde rule to the part of f located by
+// p: if the part is of the form ~~x, it is replaced by x, otherwise ~~
+// is inserted in front of it.
+func DoubleTilde(f Formula, p Path) (Formula, error) {
+	part, err := formulaAt(f, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if n, ok := part.(Negation); ok {
+		if inner, ok := n.Formula.(Negation); ok {
+			return ReplaceAt(f, p, inner.Formula)
+		}
+	}
+	return ReplaceAt(f, p, Negation{Negation{part}})
+}
+
+func doubleTilde(_ Context, premises []Formula, conclusion Formula) error {
+	if err := expectPremises(premises, 1); err != nil {
+		return err
+	}
+	if _, err := findDoubleTilde(premises[0], conclusion); err != nil {
+		return err
+	}
+	return nil
+}
+
+// findDoubleTilde returns the Path in from at which applying DoubleTilde
+// gives to.
+func findDoubleTilde(from, to Formula) (Path, error) {
+	if p, ok := findApplication(from, to, DoubleTilde); ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("%s does not differ from %s by a single ~~",
+		to, from)
+}
+
+// findApplication returns the Path of the part of from at which apply
+// gives to.
+func findApplication(from, to Formula, apply func(Formula, Path) (Formula, error)) (Path, bool) {
+	for _, part := range Parts(from) {
+		if isTerm(part.Node) {
+			continue
+		}
+		applied, err := apply(from, part.Path)
+		if err == nil && Equal(applied, to) {
+			return part.Path, true
+		}
+	}
+	return nil, false
+}
+
+// Detach applies the rule of detachment: if x and <x⊃y> are theorems,
+// then so is y.
+func Detach(x, implication Formula) (Formula, error) {
+	c, ok := implication.(Compound)
+	if !ok || c.Kind != IF_THEN {
+		return nil, fmt.Errorf("%s is not of the form <x⊃y>", implication)
+	}
+	if !Equal(c.Left, x) {
+		return nil, fmt.Errorf("%s is not the condition of %s", x, implication)
+	}
+	return c.Right, nil
+}
+
+func detachment(_ Context, premises []Formula, conclusion Formula) error {
+	if err := expectPremises(premises, 2); err != nil {
+		return err
+	}
+	y, err := Detach(premises[0], premises[1])
+	if err != nil {
+		// The premises may be given in the other order.
+		var otherErr error
+		if y, otherErr = Detach(premises[1], premises[0]); otherErr != nil {
+			return err
+		}
+	}
+	return expectConclusion(conclusion, y)
+}
+
+// Contrapositive applies the contrapositive rule to the part of f
+// located by p, turning <x⊃y> into <~y⊃~x>.  The rule also allows the
+// reverse; apply Contrapositive and then DoubleTilde to achieve that.
+func Contrapositive(f Formula, p Path) (Formula, error) {
+	part, err := formulaAt(f, p)
+	if err != nil {
+		return nil, err
+	}
+	c, ok := part.(Compound)
+	if !ok || c.Kind != IF_THEN {
+		return nil, fmt.Errorf("%s at %s is not of the form <x⊃y>", part, p)
+	}
+	return ReplaceAt(f, p, Compound{
+		Kind:  IF_THEN,
+		Left:  Negation{c.Right},
+		Right: Negation{c.Left},
+	})
+}
+
+// DeMorgan applies De Morgan's rule to the part of f located by p,
+// turning <~x∧~y> into ~<x∨y> and vice versa.
+func DeMorgan(f Formula, p Path) (Formula, error) {
+	part, err := formulaAt(f, p)
+	if err != nil {
+		return nil, err
+	}
+	switch part := part.(type) {
+	case Compound:
+		left, leftOK := part.Left.(Negation)
+		right, rightOK := part.Right.(Negation)
+		if part.Kind == AND && leftOK && rightOK {
+			return ReplaceAt(f, p, Negation{Compound{
+				Kind:  OR,
+				Left:  left.Formula,
+				Right: right.Formula,
+			}})
+		}
+	case Negation:
+		if c, ok := part.Formula.(Compound); ok && c.Kind == OR {
+			return ReplaceAt(f, p, Compound{
+				Kind:  AND,
+				Left:  Negation{c.Left},
+				Right: Negation{c.Right},
+			})
+		}
+	}
+	return nil, fmt.Errorf("%s at %s is not of the form <~x∧~y> or ~<x∨y>",
+		part, p)
+}
+
+// Switcheroo applies the switcheroo rule to the part of f located by p,
+// turning <x∨y> into <~x⊃y> and vice versa.
+func Switcheroo(f Formula, p Path) (Formula, error) {
+	part, err := formulaAt(f, p)
+	if err != nil {
+		return nil, err
+	}
+	if c, ok := part.(Compound); ok {
+		switch c.Kind {
+		case OR:
+			return ReplaceAt(f, p, Compound{
+				Kind:  IF_THEN,
+				Left:  Negation{c.Left},
+				Right: c.Right,
+			})
+		case IF_THEN:
+			if left, ok := c.Left.(Negation); ok {
+				return ReplaceAt(f, p, Compound{
+					Kind:  OR,
+					Left:  left.Formula,
+					Right: c.Right,
+				})
+			}
+		}
+	}
+	return nil, fmt.Errorf("%s at %s is not of the form <x∨y> or <~x⊃y>",
+		part, p)
+}
+
+// formulaAt returns the part of f located by p, which must be a Formula.
+func formulaAt(f Formula, p Path) (Formula, error) {
+	part, err := At(f, p)
+	if err != nil {
+		return nil, err
+	}
+	if isTerm(part) {
+		return nil, fmt.Errorf("%s at %s is not a formula", part, p)
+	}
+	return part.(Formula), nil
+}
+
+// interchangeable returns a Rule for two forms that may replace each
+// other in any part of a theorem, where apply turns one form into the
+// other.  forms describes the two forms in errors.
+func interchangeable(apply func(Formula, Path) (Formula, error), forms string) Rule {
+	return func(_ Context, premises []Formula, conclusion Formula) error {
+		if err := expectPremises(premises, 1); err != nil {
+			return err
+		}
+		if _, ok := findApplication(premises[0], conclusion, apply); ok {
+			return nil
+		}
+		if _, ok := findApplication(conclusion, premises[0], apply); ok {
+			return nil
+		}
+		return fmt.Errorf("%s does not differ from %s by interchanging %s",
+			conclusion, premises[0], forms)
+	}
+}
@@ -0,0 +1,176 @@
+package tnt
+
+import "testing"
+
+// checkRule verifies that the named Rule accepts or rejects a
+// conclusion, and when rejecting, gives the expected error.
+func checkRule(t *testing.T, name string, premises []string, conclusion string, expectedErr string) {
+	t.Helper()
+
+	rule, ok := LookupRule(name)
+	if !ok {
+		t.Fatalf("no rule named %q", name)
+	}
+
+	formulas := make([]Formula, len(premises))
+	for i, p := range premises {
+		formulas[i] = mustParse(t, p)
+	}
+
+	err := rule(Context{}, formulas, mustParse(t, conclusion))
+	switch {
+	case expectedErr == "" && err != nil:
+		t.Errorf("%s %v ⊢ %s: unexpected error: %s",
+			name, premises, conclusion, err)
+	case expectedErr != "" && err == nil:
+		t.Errorf("%s %v ⊢ %s: expected error %q",
+			name, premises, conclusion, expectedErr)
+	case expectedErr != "" && err.Error() != expectedErr:
+		t.Errorf("%s %v ⊢ %s: expected error %q but got %q",
+			name, premises, conclusion, expectedErr, err)
+	}
+}
+
+func TestJoining(t *testing.T) {
+	joined := Join(mustParse(t, "a=0"), mustParse(t, "~b=S0"))
+	if got := joined.String(); got != "<a=0∧~b=S0>" {
+		t.Errorf("expected <a=0∧~b=S0> but got %s", got)
+	}
+
+	checkRule(t, "joining", []string{"a=0", "~b=S0"}, "<a=0∧~b=S0>", "")
+	checkRule(t, "joining", []string{"a=0", "~b=S0"}, "<~b=S0∧a=0>", "")
+	checkRule(t, "joining", []string{"a=0", "~b=S0"}, "<a=0∨~b=S0>",
+		"expected <a=0∧~b=S0> but got <a=0∨~b=S0>")
+	checkRule(t, "joining", []string{"a=0"}, "<a=0∧a=0>",
+		"expected 2 premises but got 1")
+}
+
+func TestSeparation(t *testing.T) {
+	x, y, err := Separate(mustParse(t, "<a=0∧<b=0∨c=0>>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x.String() != "a=0" || y.String() != "<b=0∨c=0>" {
+		t.Errorf("expected a=0 and <b=0∨c=0> but got %s and %s", x, y)
+	}
+
+	checkRule(t, "separation", []string{"<a=0∧b=0>"}, "a=0", "")
+	checkRule(t, "separation", []string{"<a=0∧b=0>"}, "b=0", "")
+	checkRule(t, "separation", []string{"<a=0∧b=0>"}, "c=0",
+		"expected a=0 or b=0 but got c=0")
+	checkRule(t, "separation", []string{"<a=0∨b=0>"}, "a=0",
+		"<a=0∨b=0> is not of the form <x∧y>")
+}
+
+func TestDoubleTilde(t *testing.T) {
+	f := mustParse(t, "<~~a=0∧∀b:b=b>")
+	for _, test := range []struct {
+		Path     Path
+		Expected string
+	}{
+		{Path{}, "~~<~~a=0∧∀b:b=b>"},
+		{Path{LEFT}, "<a=0∧∀b:b=b>"},
+		{Path{LEFT, BODY}, "<~~~~a=0∧∀b:b=b>"},
+		{Path{RIGHT, BODY}, "<~~a=0∧∀b:~~b=b>"},
+	} {
+		got, err := DoubleTilde(f, test.Path)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.Path, err)
+		} else if got.String() != test.Expected {
+			t.Errorf("%s: expected %s but got %s", test.Path, test.Expected, got)
+		}
+	}
+	if _, err := DoubleTilde(f, Path{RIGHT, BODY, LEFT}); err == nil {
+		t.Error("expected error applying double-tilde to a term")
+	}
+
+	checkRule(t, "double-tilde", []string{"<~~a=0∧∀b:b=b>"}, "<a=0∧∀b:b=b>", "")
+	checkRule(t, "double-tilde", []string{"<a=0∧∀b:b=b>"}, "<a=0∧∀b:~~b=b>", "")
+	checkRule(t, "double-tilde", []string{"~a=0"}, "~~~a=0", "")
+	checkRule(t, "double-tilde", []string{"<a=0∧∀b:b=b>"}, "<~~a=0∧~~∀b:b=b>",
+		"<~~a=0∧~~∀b:b=b> does not differ from <a=0∧∀b:b=b> by a single ~~")
+	checkRule(t, "double-tilde", []string{"~a=0"}, "a=0",
+		"a=0 does not differ from ~a=0 by a single ~~")
+}
+
+func TestDetachment(t *testing.T) {
+	y, err := Detach(mustParse(t, "a=0"), mustParse(t, "<a=0⊃~b=0>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if y.String() != "~b=0" {
+		t.Errorf("expected ~b=0 but got %s", y)
+	}
+
+	checkRule(t, "detachment", []string{"a=0", "<a=0⊃~b=0>"}, "~b=0", "")
+	checkRule(t, "detachment", []string{"<a=0⊃~b=0>", "a=0"}, "~b=0", "")
+	checkRule(t, "detachment", []string{"a=0", "<a=0⊃~b=0>"}, "b=0",
+		"expected ~b=0 but got b=0")
+	checkRule(t, "detachment", []string{"b=0", "<a=0⊃~b=0>"}, "~b=0",
+		"b=0 is not the condition of <a=0⊃~b=0>")
+	checkRule(t, "detachment", []string{"a=0", "<a=0∧~b=0>"}, "~b=0",
+		"<a=0∧~b=0> is not of the form <x⊃y>")
+}
+
+func TestInterchangeableRules(t *testing.T) {
+	for _, test := range []struct {
+		Rule  string
+		Apply func(Formula, Path) (Formula, error)
+		From  string
+		Path  Path
+		To    string
+	}{
+		{"contrapositive", Contrapositive, "<a=0⊃b=0>", Path{}, "<~b=0⊃~a=0>"},
+		{"De Morgan", DeMorgan, "<~a=0∧~b=0>", Path{}, "~<a=0∨b=0>"},
+		{"De Morgan", DeMorgan, "~<a=0∨b=0>", Path{}, "<~a=0∧~b=0>"},
+		{"switcheroo", Switcheroo, "<a=0∨b=0>", Path{}, "<~a=0⊃b=0>"},
+		{"switcheroo", Switcheroo, "<~a=0⊃b=0>", Path{}, "<a=0∨b=0>"},
+		{"contrapositive", Contrapositive, "~<a=0⊃b=0>", Path{BODY}, "~<~b=0⊃~a=0>"},
+		{"De Morgan", DeMorgan, "∀a:<~a=0∧~b=0>", Path{BODY}, "∀a:~<a=0∨b=0>"},
+		{"switcheroo", Switcheroo, "<0=0∧<a=0∨b=0>>", Path{RIGHT}, "<0=0∧<~a=0⊃b=0>>"},
+	} {
+		got, err := test.Apply(mustParse(t, test.From), test.Path)
+		if err != nil {
+			t.Errorf("%s %s: unexpected error: %s", test.Rule, test.From, err)
+		} else if got.String() != test.To {
+			t.Errorf("%s %s: expected %s but got %s",
+				test.Rule, test.From, test.To, got)
+		}
+
+		checkRule(t, test.Rule, []string{test.From}, test.To, "")
+		checkRule(t, test.Rule, []string{test.To}, test.From, "")
+	}
+
+	if _, err := Switcheroo(mustParse(t, "<0=0∧<a=0⊃b=0>>"), Path{RIGHT}); err == nil ||
+		err.Error() != "<a=0⊃b=0> at /RIGHT is not of the form <x∨y> or <~x⊃y>" {
+		t.Errorf("expected switcheroo to fail at /RIGHT but got %v", err)
+	}
+	if _, err := Contrapositive(mustParse(t, "a=0"), Path{LEFT}); err == nil ||
+		err.Error() != "a at /LEFT is not a formula" {
+		t.Errorf("expected contrapositive to fail on a term but got %v", err)
+	}
+
+	checkRule(t, "contrapositive", []string{"<a=0⊃b=0>"}, "<~a=0⊃~b=0>",
+		"<~a=0⊃~b=0> does not differ from <a=0⊃b=0> by interchanging <x⊃y> and <~y⊃~x>")
+	checkRule(t, "De Morgan", []string{"<~a=0∧b=0>"}, "~<a=0∨~b=0>",
+		"~<a=0∨~b=0> does not differ from <~a=0∧b=0> by interchanging <~x∧~y> and ~<x∨y>")
+	checkRule(t, "switcheroo", []string{"<a=0⊃b=0>"}, "<a=0∨b=0>",
+		"<a=0∨b=0> does not differ from <a=0⊃b=0> by interchanging <x∨y> and <~x⊃y>")
+	checkRule(t, "switcheroo", []string{"<<a=0∨b=0>∧<a=0∨b=0>>"},
+		"<<~a=0⊃b=0>∧<~a=0⊃b=0>>",
+		"<<~a=0⊃b=0>∧<~a=0⊃b=0>> does not differ from <<a=0∨b=0>∧<a=0∨b=0>> by interchanging <x∨y> and <~x⊃y>")
+}
+
+func TestPropositionalDerivation(t *testing.T) {
+	registerTestRules(t)
+	// From <P∨Q> and ~P, derive Q.
+	var d Derivation
+	or := d.Add(mustParse(t, "<a=0∨b=0>"), "test given")
+	notP := d.Add(mustParse(t, "~a=0"), "test given")
+	ifThen := d.Add(mustParse(t, "<~a=0⊃b=0>"), "switcheroo", or)
+	d.Add(mustParse(t, "b=0"), "detachment", notP, ifThen)
+
+	if err := Check(d); err != nil {
+		t.Fatal(err)
+	}
+}
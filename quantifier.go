@@ -0,0 +1,213 @@
+package tnt
+
+import "fmt"
+
+// This file implements the rules of Chapter 8 that deal with
+// quantifiers.  As with the propositional rules, each has a function
+// that applies it and a registered Rule that verifies it.
+
+func init() {
+	RegisterRule("specification", specification)
+	RegisterRule("generalization", generalization)
+	RegisterRule("interchange", interchange)
+	RegisterRule("existence", existence)
+}
+
+// Specify applies the rule of specification: if ∀u:x is a theorem, then
+// so is x with t in place of every free u.  t must not contain any
+// Variable that is quantified in x.
+func Specify(f Formula, t Term) (Formula, error) {
+	q, ok := f.(Quantification)
+	if !ok || q.Kind != FOR_ALL {
+		return nil, fmt.Errorf("%s is not of the form ∀u:x", f)
+	}
+	quantified := t.Variables().Intersection(quantifiedVariables(q.Formula))
+	if len(quantified) != 0 {
+		return nil, fmt.Errorf("%s contains %s, which is quantified in %s",
+			t, quantified.sorted()[0], q.Formula)
+	}
+	return Substitute(q.Formula, q.Variable, t)
+}
+
+func specification(_ Context, premises []Formula, conclusion Formula) error {
+	if err := expectPremises(premises, 1); err != nil {
+		return err
+	}
+	q, ok := premises[0].(Quantification)
+	if !ok || q.Kind != FOR_ALL {
+		return fmt.Errorf("%s is not of the form ∀u:x", premises[0])
+	}
+	t, ok := match(q.Formula, q.Variable, conclusion)
+	if !ok {
+		return fmt.Errorf("%s is not %s with a term in place of %s",
+			conclusion, q.Formula, q.Variable)
+	}
+	if t == nil {
+		t = q.Variable
+	}
+	specified, err := Specify(q, t)
+	if err != nil {
+		return err
+	}
+	return expectConclusion(conclusion, specified)
+}
+
+// Generalize applies the rule of generalization: if x is a theorem in
+// which u is free, then so is ∀u:x.
+//
+// Inside a fantasy, u must also not be free in the fantasy's premise.
+// Generalize cannot know that, but the registered Rule checks it.
+func Generalize(f Formula, u Variable) (Formula, error) {
+	if _, ok := f.FreeVariables()[u]; !ok {
+		return nil, fmt.Errorf("%s is not free in %s", u, f)
+	}
+	return Quantification{
+		Kind:     FOR_ALL,
+		Variable: u,
+		Formula:  f,
+	}, nil
+}
+
+func generalization(ctx Context, premises []Formula, conclusion Formula) error {
+	if err := expectPremises(premises, 1); err != nil {
+		return err
+	}
+	q, ok := conclusion.(Quantification)
+	if !ok || q.Kind != FOR_ALL {
+		return fmt.Errorf("%s is not of the form ∀u:x", conclusion)
+	}
+	generalized, err := Generalize(premises[0], q.Variable)
+	if err != nil {
+		return err
+	}
+	if err := expectConclusion(conclusion, generalized); err != nil {
+		return err
+	}
+	for _, premise := range ctx.Fantasies {
+		if _, ok := premise.FreeVariables()[q.Variable]; ok {
+			return fmt.Errorf("%s is free in the fantasy premise %s",
+				q.Variable, premise)
+		}
+	}
+	return nil
+}
+
+// Interchange applies the rule of interchange to the part of f located
+// by p: if the part is of the form ∀u:~x, it is replaced by ~∃u:x, and
+// vice versa.
+func Interchange(f Formula, p Path) (Formula, error) {
+	part, err := At(f, p)
+	if err != nil {
+		return nil, err
+	}
+	switch part := part.(type) {
+	case Quantification:
+		if n, ok := part.Formula.(Negation); ok && part.Kind == FOR_ALL {
+			return ReplaceAt(f, p, Negation{Quantification{
+				Kind:     THERE_EXISTS,
+				Variable: part.Variable,
+				Formula:  n.Formula,
+			}})
+		}
+	case Negation:
+		if q, ok := part.Formula.(Quantification); ok && q.Kind == THERE_EXISTS {
+			return ReplaceAt(f, p, Quantification{
+				Kind:     FOR_ALL,
+				Variable: q.Variable,
+				Formula:  Negation{q.Formula},
+			})
+		}
+	}
+	return nil, fmt.Errorf("%s at %s is not of the form ∀u:~x or ~∃u:x",
+		part, p)
+}
+
+func interchange(_ Context, premises []Formula, conclusion Formula) error {
+	if err := expectPremises(premises, 1); err != nil {
+		return err
+	}
+	if _, ok := findApplication(premises[0], conclusion, Interchange); !ok {
+		return fmt.Errorf("%s does not differ from %s by interchanging ∀u:~ and ~∃u:",
+			conclusion, premises[0])
+	}
+	return nil
+}
+
+// Exist applies the rule of existence: the appearances of t in f at the
+// given Paths are replaced by u, and ∃u: is placed in front.  With no
+// Paths, every appearance of t is replaced.
+//
+// u must not otherwise occur in f, and the Variables of t must be free
+// wherever it is replaced.
+func Exist(f Formula, t Term, u Variable, paths ...Path) (Formula, error) {
+	if _, ok := f.Variables()[u]; ok {
+		return nil, fmt.Errorf("%s already occurs in %s", u, f)
+	}
+	if len(paths) == 0 {
+		for _, part := range Parts(f) {
+			if isTerm(part.Node) && EqualTerms(part.Node.(Term), t) {
+				paths = append(paths, part.Path)
+			}
+		}
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("%s does not occur in %s", t, f)
+		}
+	}
+
+	replaced := f
+	for _, p := range paths {
+		part, err := At(f, p)
+		if err != nil {
+			return nil, err
+		}
+		if !isTerm(part) || !EqualTerms(part.(Term), t) {
+			return nil, fmt.Errorf("%s at %s is not %s", part, p, t)
+		}
+		for i := range p {
+			q, ok := mustAt(f, p[:i]).(Quantification)
+			if _, quantified := t.Variables()[q.Variable]; ok && quantified {
+				return nil, fmt.Errorf("%s at %s is bound by the quantification at %s",
+					q.Variable, p, p[:i])
+			}
+		}
+		if replaced, err = ReplaceAt(replaced, p, u); err != nil {
+			return nil, err
+		}
+	}
+
+	return Quantification{
+		Kind:     THERE_EXISTS,
+		Variable: u,
+		Formula:  replaced,
+	}, nil
+}
+
+// mustAt returns the part of n at p, which is known to exist.
+func mustAt(n Node, p Path) Node {
+	part, err := At(n, p)
+	if err != nil {
+		panic(err)
+	}
+	return part
+}
+
+func existence(_ Context, premises []Formula, conclusion Formula) error {
+	if err := expectPremises(premises, 1); err != nil {
+		return err
+	}
+	q, ok := conclusion.(Quantification)
+	if !ok || q.Kind != THERE_EXISTS {
+		return fmt.Errorf("%s is not of the form ∃u:x", conclusion)
+	}
+	if _, ok := premises[0].Variables()[q.Variable]; ok {
+		return fmt.Errorf("%s already occurs in %s", q.Variable, premises[0])
+	}
+	t, ok := match(q.Formula, q.Variable, premises[0])
+	if !ok || t == nil {
+		return fmt.Errorf("%s is not %s with %s in place of a term",
+			q.Formula, premises[0], q.Variable)
+	}
+	// The match guarantees the result, but not that t stays free.
+	_, err := Substitute(q.Formula, q.Variable, t)
+	return err
+}
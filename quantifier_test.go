@@ -0,0 +1,137 @@
+package tnt
+
+import "testing"
+
+func TestSpecification(t *testing.T) {
+	specified, err := Specify(mustParse(t, "∀a:∃b:a=Sb"), mustParseTerm(t, "(c+S0)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := specified.String(); got != "∃b:(c+S0)=Sb" {
+		t.Errorf("expected ∃b:(c+S0)=Sb but got %s", got)
+	}
+	if _, err := Specify(mustParse(t, "∀a:∃b:a=Sb"), mustParseTerm(t, "Sb")); err == nil ||
+		err.Error() != "Sb contains b, which is quantified in ∃b:a=Sb" {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	checkRule(t, "specification", []string{"∀a:(a+0)=a"}, "(S0+0)=S0", "")
+	checkRule(t, "specification", []string{"∀a:(a+0)=a"}, "(a+0)=a", "")
+	checkRule(t, "specification", []string{"∀a:(a+0)=a"}, "(b+0)=b", "")
+	checkRule(t, "specification", []string{"∀a:Sa=SSS0"}, "SS0=SSS0", "")
+	checkRule(t, "specification", []string{"∀a:<a=0∨∃a:a=0>"}, "<0=0∨∃a:a=0>", "")
+	checkRule(t, "specification", []string{"∀a:(a+0)=a"}, "(S0+0)=0",
+		"(S0+0)=0 is not (a+0)=a with a term in place of a")
+	checkRule(t, "specification", []string{"∀a:∃b:a=Sb"}, "∃b:b=Sb",
+		"b contains b, which is quantified in ∃b:a=Sb")
+	checkRule(t, "specification", []string{"∃a:a=0"}, "0=0",
+		"∃a:a=0 is not of the form ∀u:x")
+}
+
+func TestGeneralization(t *testing.T) {
+	checkRule(t, "generalization", []string{"(a+0)=a"}, "∀a:(a+0)=a", "")
+	checkRule(t, "generalization", []string{"(a+0)=a"}, "∀b:(a+0)=a",
+		"b is not free in (a+0)=a")
+	checkRule(t, "generalization", []string{"(a+0)=a"}, "∃a:(a+0)=a",
+		"∃a:(a+0)=a is not of the form ∀u:x")
+	checkRule(t, "generalization", []string{"(a+0)=a"}, "∀a:(a+0)=S0",
+		"expected ∀a:(a+0)=a but got ∀a:(a+0)=S0")
+
+	rule, _ := LookupRule("generalization")
+	ctx := Context{Fantasies: []Formula{mustParse(t, "∃b:a=Sb")}}
+	premises := []Formula{mustParse(t, "a=a")}
+	err := rule(ctx, premises, mustParse(t, "∀a:a=a"))
+	if err == nil || err.Error() != "a is free in the fantasy premise ∃b:a=Sb" {
+		t.Errorf("unexpected error %v", err)
+	}
+	premises = []Formula{mustParse(t, "b=b")}
+	if err := rule(ctx, premises, mustParse(t, "∀b:b=b")); err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+}
+
+func TestInterchange(t *testing.T) {
+	interchanged, err := Interchange(mustParse(t, "<0=0∧∀a:~a=S0>"), Path{RIGHT})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := interchanged.String(); got != "<0=0∧~∃a:a=S0>" {
+		t.Errorf("expected <0=0∧~∃a:a=S0> but got %s", got)
+	}
+
+	checkRule(t, "interchange", []string{"∀a:~a=S0"}, "~∃a:a=S0", "")
+	checkRule(t, "interchange", []string{"~∃a:a=S0"}, "∀a:~a=S0", "")
+	checkRule(t, "interchange", []string{"∀b:∀a:~a=Sb"}, "∀b:~∃a:a=Sb", "")
+	checkRule(t, "interchange", []string{"∀b:∀a:~a=Sb"}, "~∃b:∀a:a=Sb",
+		"~∃b:∀a:a=Sb does not differ from ∀b:∀a:~a=Sb by interchanging ∀u:~ and ~∃u:")
+	checkRule(t, "interchange", []string{"∀a:a=a"}, "~∃a:~a=a",
+		"~∃a:~a=a does not differ from ∀a:a=a by interchanging ∀u:~ and ~∃u:")
+}
+
+func TestExistence(t *testing.T) {
+	for _, test := range []struct {
+		formula  string
+		term     string
+		paths    []Path
+		expected string
+		err      string
+	}{
+		{"(S0+S0)=SS0", "S0", nil, "∃b:(b+b)=SS0", ""},
+		{"(S0+S0)=SS0", "S0", []Path{{LEFT, LEFT}}, "∃b:(b+S0)=SS0", ""},
+		{"<a=a∧∀c:c=c>", "a", nil, "∃b:<b=b∧∀c:c=c>", ""},
+		{"(S0+S0)=SS0", "0", nil, "", "0 does not occur in (S0+S0)=SS0"},
+		{"(b+0)=b", "0", nil, "", "b already occurs in (b+0)=b"},
+		{"(S0+S0)=SS0", "S0", []Path{{RIGHT}}, "", "SS0 at /RIGHT is not S0"},
+		{"∀c:(c+a)=(a+c)", "(c+a)", nil, "",
+			"c at /BODY/LEFT is bound by the quantification at /"},
+	} {
+		f, err := Exist(mustParse(t, test.formula), mustParseTerm(t, test.term),
+			"b", test.paths...)
+		switch {
+		case test.err != "" && (err == nil || err.Error() != test.err):
+			t.Errorf("%s: expected error %q but got %v", test.formula, test.err, err)
+		case test.err == "" && err != nil:
+			t.Errorf("%s: unexpected error %s", test.formula, err)
+		case test.err == "" && f.String() != test.expected:
+			t.Errorf("%s: expected %s but got %s", test.formula, test.expected, f)
+		}
+	}
+
+	checkRule(t, "existence", []string{"(S0+S0)=SS0"}, "∃b:(b+S0)=SS0", "")
+	checkRule(t, "existence", []string{"(S0+S0)=SS0"}, "∃b:(b+b)=Sb", "")
+	checkRule(t, "existence", []string{"(S0+S0)=SS0"}, "∃b:(b+b)=b",
+		"(b+b)=b is not (S0+S0)=SS0 with b in place of a term")
+	checkRule(t, "existence", []string{"(S0+S0)=SS0"}, "∀b:(b+b)=b",
+		"∀b:(b+b)=b is not of the form ∃u:x")
+	checkRule(t, "existence", []string{"(b+0)=b"}, "∃b:(b+0)=b",
+		"b already occurs in (b+0)=b")
+	checkRule(t, "existence", []string{"∀c:(c+a)=(a+c)"}, "∃b:∀c:b=(a+c)",
+		"cannot substitute (c+a) for b: c would be captured by the quantification at /")
+}
+
+func TestQuantifierDerivation(t *testing.T) {
+	registerTestRules(t)
+	var d Derivation
+	axiom := d.Add(mustParse(t, "∀a:(a+0)=a"), "test given")
+	specified := d.Add(mustParse(t, "(b+0)=b"), "specification", axiom)
+
+	d.Push(mustParse(t, "b=0"))
+	carried := d.Add(mustParse(t, "(b+0)=b"), CARRY_OVER, specified)
+	d.Add(mustParse(t, "∀b:(b+0)=b"), "generalization", carried)
+	if _, err := d.Pop(); err != nil {
+		t.Fatal(err)
+	}
+
+	err := Check(d)
+	expected := "step 6: generalization: b is free in the fantasy premise b=0"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %q but got %v", expected, err)
+	}
+
+	d.Steps = d.Steps[:specified]
+	d.Add(mustParse(t, "∀b:(b+0)=b"), "generalization", specified)
+	d.Add(mustParse(t, "∃c:(c+0)=c"), "existence", specified)
+	if err := Check(d); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,219 @@
+// Package repl provides an interactive shell for building TNT
+// derivations one step at a time.
+//
+// Each line of input is either a step, written as in a derivation file
+// but without its number, or one of these commands:
+//
+//	[ x       enter a fantasy with the premise x (or: push x)
+//	]         leave the innermost fantasy (or: pop)
+//	undo      remove the steps added by the last command
+//	show      print the whole derivation
+//	save f    write the derivation to the file f
+//	help      list the commands
+//	quit      end the session
+//
+// Every step is checked as it is entered, and rejected if it does not
+// follow from the steps it refers to.
+package repl
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jeremyhuiskamp/tnt"
+	"github.com/jeremyhuiskamp/tnt/derivation"
+)
+
+const help = `enter a step as: formula (rule: 1, 2)
+commands:
+	[ x       enter a fantasy with the premise x (or: push x)
+	]         leave the innermost fantasy (or: pop)
+	undo      remove the steps added by the last command
+	show      print the whole derivation
+	save f    write the derivation to the file f
+	help      list the commands
+	quit      end the session
+`
+
+// Prompt is written before each line of input is read.
+const Prompt = "tnt> "
+
+// ErrQuit is returned by Execute when the user ends the session.
+var ErrQuit = errors.New("quit")
+
+// Session is a derivation being built interactively.
+type Session struct {
+	Derivation tnt.Derivation
+	// added holds the number of steps added by each command, for undo
+	added []int
+}
+
+// Run reads lines from r and executes them, writing the results to w,
+// until r ends or the user quits.  Errors in the input are written to w
+// and do not end the session.
+func Run(s *Session, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	for {
+		if _, err := io.WriteString(w, Prompt); err != nil {
+			return err
+		}
+		if !scanner.Scan() {
+			io.WriteString(w, "\n")
+			return scanner.Err()
+		}
+		err := s.Execute(scanner.Text(), w)
+		if err == ErrQuit {
+			return nil
+		}
+		if err != nil {
+			writeError(w, err)
+		}
+	}
+}
+
+// writeError writes err, followed by an excerpt showing the position of
+// the bad character if it is a parse error.
+func writeError(w io.Writer, err error) {
+	fmt.Fprintf(w, "error: %s\n", err)
+	var perr *tnt.ParseError
+	if errors.As(err, &perr) {
+		if excerpt := perr.Excerpt(); excerpt != "" {
+			fmt.Fprintln(w, excerpt)
+		}
+	}
+}
+
+// Execute executes one line of input, writing any new steps to w.
+func (s *Session) Execute(input string, w io.Writer) error {
+	command, arg, _ := strings.Cut(strings.TrimSpace(input), " ")
+	switch command {
+	case "":
+		return nil
+	case "help":
+		_, err := io.WriteString(w, help)
+		return err
+	case "quit", "exit":
+		return ErrQuit
+	case "show":
+		return derivation.Write(w, s.Derivation)
+	case "save":
+		return s.save(strings.TrimSpace(arg), w)
+	case "undo":
+		return s.undo(w)
+	case "]", "pop":
+		return s.pop(w)
+	}
+
+	if strings.HasPrefix(command, "[") || command == "push" {
+		return s.push(input, w)
+	}
+
+	step, err := derivation.ParseStep(input)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(step.Rule, tnt.POP) {
+		// an annotated ], such as "] (pop out of fantasy)"
+		return s.pop(w)
+	}
+	return s.add(w, func() error {
+		s.Derivation.Steps = append(s.Derivation.Steps, step)
+		return nil
+	})
+}
+
+// add applies a change to the derivation, keeping it only if the
+// derivation still checks.  The new steps are written to w.
+func (s *Session) add(w io.Writer, change func() error) error {
+	before := len(s.Derivation.Steps)
+	err := change()
+	if err == nil {
+		err = tnt.Check(s.Derivation)
+	}
+	if err != nil {
+		s.Derivation.Steps = s.Derivation.Steps[:before]
+		return err
+	}
+	s.added = append(s.added, len(s.Derivation.Steps)-before)
+	return s.writeLast(w, len(s.Derivation.Steps)-before)
+}
+
+// writeLast writes the last n steps of the derivation, laid out as in
+// the whole derivation.
+func (s *Session) writeLast(w io.Writer, n int) error {
+	var b bytes.Buffer
+	if err := derivation.Write(&b, s.Derivation); err != nil {
+		return err
+	}
+	lines := strings.SplitAfter(strings.TrimSuffix(b.String(), "\n"), "\n")
+	_, err := io.WriteString(w, strings.Join(lines[len(lines)-n:], "")+"\n")
+	return err
+}
+
+// push enters a fantasy whose premise follows the command in input.
+func (s *Session) push(input string, w io.Writer) error {
+	trimmed := strings.TrimLeft(input, " \t")
+	prefix := len(input) - len(trimmed) + 1
+	if !strings.HasPrefix(trimmed, "[") {
+		prefix = len(input) - len(trimmed) + len("push")
+	}
+
+	// Blank out the command so that positions in the formula are
+	// positions in the input.
+	src := strings.Repeat(" ", prefix) + input[prefix:]
+	if strings.TrimSpace(src) == "" {
+		return fmt.Errorf("expected premise after %s", strings.TrimSpace(input[:prefix]))
+	}
+	premise, err := tnt.ParseFormula(src)
+	if err != nil {
+		if perr, ok := err.(*tnt.ParseError); ok {
+			perr.Src = input
+		}
+		return err
+	}
+	return s.add(w, func() error {
+		s.Derivation.Push(premise)
+		return nil
+	})
+}
+
+// pop leaves the innermost fantasy.
+func (s *Session) pop(w io.Writer) error {
+	return s.add(w, func() error {
+		_, err := s.Derivation.Pop()
+		return err
+	})
+}
+
+// undo removes the steps added by the last command.
+func (s *Session) undo(w io.Writer) error {
+	if len(s.added) == 0 {
+		return fmt.Errorf("nothing to undo")
+	}
+	n := s.added[len(s.added)-1]
+	s.added = s.added[:len(s.added)-1]
+	s.Derivation.Steps = s.Derivation.Steps[:len(s.Derivation.Steps)-n]
+	_, err := fmt.Fprintf(w, "removed %d step(s)\n", n)
+	return err
+}
+
+// save writes the derivation to the named file.
+func (s *Session) save(name string, w io.Writer) error {
+	if name == "" {
+		return fmt.Errorf("expected file name after save")
+	}
+	var b bytes.Buffer
+	if err := derivation.Write(&b, s.Derivation); err != nil {
+		return err
+	}
+	if err := os.WriteFile(name, b.Bytes(), 0644); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "saved %d step(s) to %s\n", len(s.Derivation.Steps), name)
+	return err
+}
@@ -0,0 +1,82 @@
+package repl
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "proof.tnt")
+	input := strings.Join([]string{
+		"∀a:(a+0)=a  (axiom 2)",
+		"(b+0)=b  (specification: 1)",
+		"[ b=0",
+		"(b+0)=b  (carry over: 2)",
+		"]",
+		"undo",
+		"(b+0)=_  (carry over: 2)",
+		"(b+0)=0  (carry over: 2)",
+		"pop",
+		"save " + name,
+		"quit",
+		"(b+0)=b  (specification: 1)",
+	}, "\n")
+
+	var s Session
+	var out strings.Builder
+	if err := Run(&s, strings.NewReader(input), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `tnt> 1  ∀a:(a+0)=a    (axiom 2)
+tnt> 2  (b+0)=b       (specification: 1)
+tnt> 3  [
+4    b=0         (premise)
+tnt> 5    (b+0)=b     (carry over: 2)
+tnt> 6  ]
+7  <b=0⊃(b+0)=b>    (fantasy rule)
+tnt> removed 2 step(s)
+tnt> error: 1:7: expected 0, S, VARIABLE or ( but got ILLEGAL "_"
+(b+0)=_  (carry over: 2)
+      ^
+tnt> error: step 6: expected (b+0)=b but got (b+0)=0
+tnt> 6  ]
+7  <b=0⊃(b+0)=b>    (fantasy rule)
+tnt> saved 7 step(s) to ` + name + `
+tnt> `
+	if got := out.String(); got != expected {
+		t.Errorf("expected\n%s\nbut got\n%s", expected, got)
+	}
+
+	saved, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(string(saved), "7  <b=0⊃(b+0)=b>    (fantasy rule)\n") {
+		t.Errorf("unexpected saved derivation:\n%s", saved)
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	for input, expected := range map[string]string{
+		"]":                      "not in a fantasy",
+		"undo":                   "nothing to undo",
+		"save":                   "expected file name after save",
+		"push":                   "expected premise after push",
+		"[ a=0>":                 "1:6: expected EOF but got >",
+		"0=0  (joining: 1, 2)":   "step 1: premise 1 is not an earlier step",
+		"∀a:a=a  (no such rule)": "step 1: unknown rule \"no such rule\"",
+	} {
+		var s Session
+		var out strings.Builder
+		err := s.Execute(input, &out)
+		if err == nil || err.Error() != expected {
+			t.Errorf("%q: expected error %q but got %v", input, expected, err)
+		}
+		if len(s.Derivation.Steps) != 0 {
+			t.Errorf("%q: expected no steps but got %v", input, s.Derivation.Steps)
+		}
+	}
+}
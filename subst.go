@@ -0,0 +1,181 @@
+package tnt
+
+import "fmt"
+
+// Substitute replaces every free occurrence of v in f with t.
+//
+// An error is returned if a Variable of t would be captured by a
+// Quantification in f, ie, if a free occurrence of v is inside a
+// Quantification of one of the Variables of t.
+func Substitute(f Formula, v Variable, t Term) (Formula, error) {
+	return substitute(f, v, t, Path{})
+}
+
+func substitute(f Formula, v Variable, t Term, path Path) (Formula, error) {
+	switch f := f.(type) {
+	case Atom:
+		return Atom{
+			Left:  substituteTerm(f.Left, v, t),
+			Right: substituteTerm(f.Right, v, t),
+		}, nil
+	case Negation:
+		formula, err := substitute(f.Formula, v, t, path.Child(BODY))
+		if err != nil {
+			return nil, err
+		}
+		return Negation{formula}, nil
+	case Compound:
+		left, err := substitute(f.Left, v, t, path.Child(LEFT))
+		if err != nil {
+			return nil, err
+		}
+		right, err := substitute(f.Right, v, t, path.Child(RIGHT))
+		if err != nil {
+			return nil, err
+		}
+		return Compound{
+			Kind:  f.Kind,
+			Left:  left,
+			Right: right,
+		}, nil
+	case Quantification:
+		if f.Variable == v {
+			// no occurrence of v inside is free
+			return f, nil
+		}
+		if _, ok := f.Formula.FreeVariables()[v]; !ok {
+			return f, nil
+		}
+		if _, ok := t.Variables()[f.Variable]; ok {
+			return nil, fmt.Errorf("cannot substitute %s for %s: "+
+				"%s would be captured by the quantification at %s",
+				t, v, f.Variable, path)
+		}
+		formula, err := substitute(f.Formula, v, t, path.Child(BODY))
+		if err != nil {
+			return nil, err
+		}
+		return Quantification{
+			Kind:     f.Kind,
+			Variable: f.Variable,
+			Formula:  formula,
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown formula type %T", f)
+}
+
+// substituteTerm replaces every occurrence of v in term with t.
+func substituteTerm(term Term, v Variable, t Term) Term {
+	switch term := term.(type) {
+	case Variable:
+		if term == v {
+			return t
+		}
+	case Successor:
+		return successor(term.Quantity, substituteTerm(term.Term, v, t))
+	case CompoundTerm:
+		return CompoundTerm{
+			Kind:  term.Kind,
+			Left:  substituteTerm(term.Left, v, t),
+			Right: substituteTerm(term.Right, v, t),
+		}
+	}
+	return term
+}
+
+// successor returns the Term with quantity S's in front of t, in the
+// same form that ParseFormula produces: successors of Numerals are
+// Numerals, and successors of Successors are combined.
+func successor(quantity int, t Term) Term {
+	if quantity == 0 {
+		return t
+	}
+	switch t := t.(type) {
+	case Numeral:
+		return t + Numeral(quantity)
+	case Successor:
+		return successor(quantity+t.Quantity, t.Term)
+	}
+	return Successor{
+		Quantity: quantity,
+		Term:     t,
+	}
+}
+
+// match finds the Term t such that substituting t for the free
+// occurrences of v in pattern gives target.  If v does not occur free in
+// pattern, t is nil and ok reports whether pattern equals target.
+//
+// Successors are matched by the number of S's they denote, so matching
+// Sa against SSS0 finds SS0.
+func match(pattern Formula, v Variable, target Formula) (t Term, ok bool) {
+	m := matcher{v: v}
+	if !m.formula(pattern, target) {
+		return nil, false
+	}
+	return m.t, true
+}
+
+type matcher struct {
+	v Variable
+	t Term
+}
+
+func (m *matcher) formula(p, target Formula) bool {
+	switch p := p.(type) {
+	case Atom:
+		target, ok := target.(Atom)
+		return ok && m.term(p.Left, target.Left) && m.term(p.Right, target.Right)
+	case Negation:
+		target, ok := target.(Negation)
+		return ok && m.formula(p.Formula, target.Formula)
+	case Compound:
+		target, ok := target.(Compound)
+		return ok && p.Kind == target.Kind &&
+			m.formula(p.Left, target.Left) && m.formula(p.Right, target.Right)
+	case Quantification:
+		target, ok := target.(Quantification)
+		if !ok || p.Kind != target.Kind || p.Variable != target.Variable {
+			return false
+		}
+		if p.Variable == m.v {
+			// v is not free inside
+			return Equal(p.Formula, target.Formula)
+		}
+		return m.formula(p.Formula, target.Formula)
+	}
+	return false
+}
+
+func (m *matcher) term(p, target Term) bool {
+	target = normalize(target)
+	switch p := normalize(p).(type) {
+	case Variable:
+		if p != m.v {
+			return EqualTerms(p, target)
+		}
+		if m.t == nil {
+			m.t = target
+			return true
+		}
+		return EqualTerms(m.t, target)
+	case Successor:
+		switch target := target.(type) {
+		case Numeral:
+			if int(target) >= p.Quantity {
+				return m.term(p.Term, target-Numeral(p.Quantity))
+			}
+		case Successor:
+			if target.Quantity >= p.Quantity {
+				return m.term(p.Term, successor(target.Quantity-p.Quantity, target.Term))
+			}
+		}
+		return false
+	case CompoundTerm:
+		target, ok := target.(CompoundTerm)
+		return ok && p.Kind == target.Kind &&
+			m.term(p.Left, target.Left) && m.term(p.Right, target.Right)
+	default:
+		return EqualTerms(p, target)
+	}
+}
@@ -0,0 +1,78 @@
+package tnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSubstitute(t *testing.T) {
+	type testCase struct {
+		Formula  string
+		Variable Variable
+		Term     string
+		Expected string
+	}
+
+	for _, test := range []testCase{
+		{"a=b", "a", "S0", "S0=b"},
+		{"a=a", "a", "(b+c)", "(b+c)=(b+c)"},
+		{"SSa=S(a·Sa)", "a", "SS0", "SSSS0=S(SS0·SSS0)"},
+		{"Sa=0", "a", "Sb", "SSb=0"},
+		{"~<a=b⊃∃c:c=a>", "a", "Sd", "~<Sd=b⊃∃c:c=Sd>"},
+		{"<∀a:a=a∧a=0>", "a", "b", "<∀a:a=a∧b=0>"},
+		{"∀b:∃c:(a+b)=c", "a", "Sd", "∀b:∃c:(Sd+b)=c"},
+		{"∀b:b=0", "a", "b", "∀b:b=0"},
+		{"<∀b:b=0∨a=b>", "a", "b", "<∀b:b=0∨b=b>"},
+	} {
+		formula, err := ParseFormula(test.Formula)
+		if err != nil {
+			t.Fatalf("error parsing %q: %s", test.Formula, err)
+		}
+		term, err := ParseFormula(test.Term + "=0")
+		if err != nil {
+			t.Fatalf("error parsing %q: %s", test.Term, err)
+		}
+		expected, err := ParseFormula(test.Expected)
+		if err != nil {
+			t.Fatalf("error parsing %q: %s", test.Expected, err)
+		}
+
+		got, err := Substitute(formula, test.Variable, term.(Atom).Left)
+		if err != nil {
+			t.Errorf("%q{%s/%s}: unexpected error: %s",
+				test.Formula, test.Term, test.Variable, err)
+		} else if !reflect.DeepEqual(got, expected) {
+			t.Errorf("%q{%s/%s}: expected %s but got %s",
+				test.Formula, test.Term, test.Variable, expected, got)
+		}
+	}
+}
+
+func TestSubstituteCapture(t *testing.T) {
+	for _, test := range []struct {
+		Formula  string
+		Variable Variable
+		Term     Term
+		Error    string
+	}{
+		{"∀b:a=b", "a", Variable("b"),
+			"cannot substitute b for a: b would be captured " +
+				"by the quantification at /"},
+		{"<0=0∧~∃c:S(a+0)=c>", "a", CompoundTerm{PLUS, Variable("c"), Numeral(1)},
+			"cannot substitute (c+S0) for a: c would be captured " +
+				"by the quantification at /RIGHT/BODY"},
+	} {
+		formula, err := ParseFormula(test.Formula)
+		if err != nil {
+			t.Fatalf("error parsing %q: %s", test.Formula, err)
+		}
+		_, err = Substitute(formula, test.Variable, test.Term)
+		if err == nil {
+			t.Errorf("%q{%s/%s}: expected error",
+				test.Formula, test.Term, test.Variable)
+		} else if err.Error() != test.Error {
+			t.Errorf("%q{%s/%s}: expected error %q but got %q",
+				test.Formula, test.Term, test.Variable, test.Error, err)
+		}
+	}
+}
@@ -17,6 +17,8 @@ package tnt
 // Term is a Numeral, Variable, Successor or CompoundTerm.
 type Term interface {
 	Variables() VariableSet
+	// String returns the Term in canonical TNT notation.
+	String() string
 }
 
 // Formula is an Atom, Negation, Compound or Quantification.
@@ -25,6 +27,9 @@ type Formula interface {
 	FreeVariables() VariableSet
 	Open() bool
 	WellFormed() bool
+	// String returns the Formula in canonical TNT notation, such that
+	// ParseFormula returns an identical Formula.
+	String() string
 }
 
 // Numeral is a Term of the form 0, S0, SS0, etc.
@@ -35,6 +40,11 @@ func (n Numeral) Variables() VariableSet {
 	return nil
 }
 
+// String returns the Numeral as a number of S's followed by 0.
+func (n Numeral) String() string {
+	return Printer{}.Term(n)
+}
+
 // Variable is a Term of the form a, b, c, d, e, a', b', etc.
 type Variable string
 
@@ -43,6 +53,11 @@ func (v Variable) Variables() VariableSet {
 	return NewVariableSet(v)
 }
 
+// String returns the name of the Variable.
+func (v Variable) String() string {
+	return string(v)
+}
+
 // Successor is a Term of the form S*x where x is a Term.
 type Successor struct {
 	Quantity int
@@ -54,6 +69,11 @@ func (s Successor) Variables() VariableSet {
 	return s.Term.Variables()
 }
 
+// String returns Quantity S's followed by the Successor's Term.
+func (s Successor) String() string {
+	return Printer{}.Term(s)
+}
+
 // CompoundTermKind is either + or *.
 type CompoundTermKind int
 
@@ -77,6 +97,11 @@ func (c CompoundTerm) Variables() VariableSet {
 	return c.Left.Variables().Union(c.Right.Variables())
 }
 
+// String returns the CompoundTerm in the form (x+y) or (x·y).
+func (c CompoundTerm) String() string {
+	return Printer{}.Term(c)
+}
+
 // Atom is a Formula in the form x=y, where x and y are Terms.
 type Atom struct {
 	Left  Term
@@ -106,6 +131,11 @@ func (a Atom) WellFormed() bool {
 	return true
 }
 
+// String returns the Atom in the form x=y.
+func (a Atom) String() string {
+	return Printer{}.Formula(a)
+}
+
 // Negation is a Formula that is the negation of another Formula.
 type Negation struct {
 	Formula Formula
@@ -131,6 +161,11 @@ func (n Negation) WellFormed() bool {
 	return n.Formula.WellFormed()
 }
 
+// String returns the contained Formula preceded by ~.
+func (n Negation) String() string {
+	return Printer{}.Formula(n)
+}
+
 // CompoundKind is "and", "or" or "if, then"
 type CompoundKind int
 
@@ -170,26 +205,14 @@ func (c Compound) Open() bool {
 
 // WellFormed returns true if both contained Formulas are WellFormed,
 // and no Variable which is free in one is quantified in the other.
+// CheckWellFormed explains which Variables are in violation.
 func (c Compound) WellFormed() bool {
-	if !c.Left.WellFormed() || !c.Right.WellFormed() {
-		return false
-	}
-
-	lv := c.Left.Variables()
-	lf := c.Left.FreeVariables()
-	lq := lv.Complement(lf)
-
-	rv := c.Right.Variables()
-	rf := c.Right.FreeVariables()
-	rq := rv.Complement(rf)
-
-	lfrq := lf.Intersection(rq)
-	rflq := rf.Intersection(lq)
+	return len(CheckWellFormed(c)) == 0
+}
 
-	// This is rather complicated to follow.  It might be
-	// good to implement an error message stating which
-	// variables in which formula are in violation.
-	return len(lfrq) == 0 && len(rflq) == 0
+// String returns the Compound in the form <x∧y>, <x∨y> or <x⊃y>.
+func (c Compound) String() string {
+	return Printer{}.Formula(c)
 }
 
 // QuantificationKind is "there exists" or "for all".
@@ -214,23 +237,25 @@ func (q Quantification) Variables() VariableSet {
 	return q.Formula.Variables()
 }
 
-// FreeVariables returns the same value as the contained Formula,
+// FreeVariables returns the FreeVariables of the contained Formula,
 // but without the Variable quantified by this Quantification.
 func (q Quantification) FreeVariables() VariableSet {
-	return q.Formula.Variables().Complement(NewVariableSet(q.Variable))
+	return q.Formula.FreeVariables().Complement(NewVariableSet(q.Variable))
 }
 
-// Open returns true if this Quantification has no FreeVariables.
+// Open returns true if this Quantification has any FreeVariables.
 func (q Quantification) Open() bool {
-	return len(q.FreeVariables()) == 0
+	return len(q.FreeVariables()) != 0
 }
 
 // WellFormed returns true if the contained Formula is WellFormed
 // and the Variable quantified is free in the contained Formula.
+// CheckWellFormed explains which rule is violated.
 func (q Quantification) WellFormed() bool {
-	if !q.Formula.WellFormed() {
-		return false
-	}
-	_, ok := q.Formula.FreeVariables()[q.Variable]
-	return ok
+	return len(CheckWellFormed(q)) == 0
+}
+
+// String returns the Quantification in the form ∀u:x or ∃u:x.
+func (q Quantification) String() string {
+	return Printer{}.Formula(q)
 }
@@ -1,6 +1,7 @@
 package tnt
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -68,3 +69,49 @@ func TestOpen(t *testing.T) {
 		}
 	}
 }
+
+func TestString(t *testing.T) {
+	for input, expected := range map[string]string{
+		"0=0":                         "0=0",
+		"SSS0=a'":                     "SSS0=a'",
+		"S S a = SS(SSS0+b)":          "SSa=SS(SSS0+b)",
+		"(0*a)=(b.(c·d))":             "(0·a)=(b·(c·d))",
+		"~~0=S0":                      "~~0=S0",
+		"<<0=0 ^ a=b> V <a=b ⊃ a=b>>": "<<0=0∧a=b>∨<a=b⊃a=b>>",
+		"Aa:Eb:(a+b)=0":               "∀a:∃b:(a+b)=0",
+		"~∀c:∃d:<(c·d)=S0∨~S(c+d)=0>": "~∀c:∃d:<(c·d)=S0∨~S(c+d)=0>",
+	} {
+		formula, err := ParseFormula(input)
+		if err != nil {
+			t.Errorf("error parsing %q: %s", input, err)
+			continue
+		}
+		if got := formula.String(); got != expected {
+			t.Errorf("expected %q to print as %q, but got %q",
+				input, expected, got)
+		}
+		reparsed, err := ParseFormula(formula.String())
+		if err != nil {
+			t.Errorf("error reparsing %q: %s", formula, err)
+		} else if !reflect.DeepEqual(formula, reparsed) {
+			t.Errorf("expected %q to round-trip to %+v, but got %+v",
+				input, formula, reparsed)
+		}
+	}
+}
+
+func TestTermString(t *testing.T) {
+	for expected, term := range map[string]Term{
+		"0":      Numeral(0),
+		"SSS0":   Numeral(3),
+		"b'":     Variable("b'"),
+		"SSa":    Successor{Quantity: 2, Term: Variable("a")},
+		"S(a+0)": Successor{Quantity: 1, Term: CompoundTerm{PLUS, Variable("a"), Numeral(0)}},
+		"(a·S0)": CompoundTerm{MULTIPLY, Variable("a"), Numeral(1)},
+	} {
+		if got := term.String(); got != expected {
+			t.Errorf("expected %+v to print as %q, but got %q",
+				term, expected, got)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package token
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Numbering assigns a codon to each symbol of TNT, in order to give
+// every string of symbols a Gödel number.
+//
+// A Gödel number is formed by writing the codons of the symbols one
+// after another in decimal, so all codons in a Numbering must have the
+// same number of digits and must not start with 0.
+//
+// Symbols are the runes of the UNICODE Dialect.
+type Numbering map[rune]int
+
+// Codons is the Numbering given in Chapter 9 of the book.
+//
+// The book treats the variables b, c, d and e as abbreviations of a′,
+// a″, a‴ and a⁗, and numbers them that way.  So b′ and c have the same
+// Gödel number, and decoding gives back variables written with a and
+// primes.  ExtendedCodons keeps them apart.
+var Codons = Numbering{
+	'0':  666,
+	'S':  123,
+	'=':  111,
+	'+':  112,
+	'·':  236,
+	'(':  362,
+	')':  323,
+	'<':  212,
+	'>':  213,
+	'a':  262,
+	'\'': 163,
+	'∧':  161,
+	'∨':  616,
+	'⊃':  633,
+	'~':  223,
+	'∃':  333,
+	'∀':  626,
+	':':  636,
+}
+
+// ExtendedCodons is Codons with codons, not in the book, for the
+// variables b, c, d and e, so that decoding gives back the variables
+// that were encoded.
+var ExtendedCodons = Numbering{
+	'0':  666,
+	'S':  123,
+	'=':  111,
+	'+':  112,
+	'·':  236,
+	'(':  362,
+	')':  323,
+	'<':  212,
+	'>':  213,
+	'a':  262,
+	'b':  263,
+	'c':  264,
+	'd':  265,
+	'e':  266,
+	'\'': 163,
+	'∧':  161,
+	'∨':  616,
+	'⊃':  633,
+	'~':  223,
+	'∃':  333,
+	'∀':  626,
+	':':  636,
+}
+
+// spell returns the symbols of a variable that have codons in n.  As in
+// the book, the letters b, c, d and e are written as a with one to four
+// primes if n has no codons for them.
+func (n Numbering) spell(variable string) string {
+	letter := rune(variable[0])
+	if _, ok := n[letter]; ok || letter < 'b' || letter > 'e' {
+		return variable
+	}
+	return "a" + strings.Repeat("'", int(letter-'a')) + variable[1:]
+}
+
+// width returns the number of digits in each codon, or an error if the
+// Numbering is not usable.
+func (n Numbering) width() (int, error) {
+	width := 0
+	seen := make(map[int]rune, len(n))
+	for symbol, codon := range n {
+		digits := strconv.Itoa(codon)
+		if codon <= 0 {
+			return 0, fmt.Errorf("codon %d for %q is not positive", codon, symbol)
+		}
+		if width == 0 {
+			width = len(digits)
+		} else if len(digits) != width {
+			return 0, fmt.Errorf("codon %d for %q does not have %d digits",
+				codon, symbol, width)
+		}
+		if other, ok := seen[codon]; ok {
+			return 0, fmt.Errorf("codon %d is used for both %q and %q",
+				codon, other, symbol)
+		}
+		seen[codon] = symbol
+	}
+	if width == 0 {
+		return 0, fmt.Errorf("numbering has no codons")
+	}
+	return width, nil
+}
+
+// Encode returns the Gödel number of a string of TNT symbols, which may
+// be written in any Dialect.  The string need not be a valid Formula,
+// but every token in it must be legal.
+func (n Numbering) Encode(src string) (*big.Int, error) {
+	if _, err := n.width(); err != nil {
+		return nil, err
+	}
+
+	var digits strings.Builder
+	s := NewScanner(src)
+	for {
+		tok, val := s.Scan()
+		if tok == EOF {
+			break
+		}
+
+		spelling := val
+		switch tok {
+		case ILLEGAL:
+			return nil, fmt.Errorf("%s: illegal symbol %q", s.Position(), val)
+		case VARIABLE:
+			spelling = n.spell(val)
+		case SUCCESSOR:
+		default:
+			spelling = tok.Symbol(UNICODE)
+		}
+
+		for _, symbol := range spelling {
+			codon, ok := n[symbol]
+			if !ok {
+				return nil, fmt.Errorf("%s: no codon for %q", s.Position(), symbol)
+			}
+			digits.WriteString(strconv.Itoa(codon))
+		}
+	}
+
+	if digits.Len() == 0 {
+		return nil, fmt.Errorf("no symbols to encode")
+	}
+	g, _ := new(big.Int).SetString(digits.String(), 10)
+	return g, nil
+}
+
+// Decode returns the string of symbols, in the UNICODE Dialect, that has
+// the Gödel number g.
+func (n Numbering) Decode(g *big.Int) (string, error) {
+	width, err := n.width()
+	if err != nil {
+		return "", err
+	}
+	if g == nil || g.Sign() <= 0 {
+		return "", fmt.Errorf("%v is not a Gödel number", g)
+	}
+
+	digits := g.String()
+	if len(digits)%width != 0 {
+		return "", fmt.Errorf("%s does not consist of %d-digit codons", digits, width)
+	}
+
+	symbols := make(map[int]rune, len(n))
+	for symbol, codon := range n {
+		symbols[codon] = symbol
+	}
+
+	var src strings.Builder
+	for i := 0; i < len(digits); i += width {
+		codon, _ := strconv.Atoi(digits[i : i+width])
+		symbol, ok := symbols[codon]
+		if !ok {
+			return "", fmt.Errorf("unknown codon %d at symbol %d", codon, i/width+1)
+		}
+		src.WriteRune(symbol)
+	}
+	return src.String(), nil
+}
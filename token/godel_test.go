@@ -0,0 +1,104 @@
+package token
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	for src, expected := range map[string]string{
+		"0=0":        "666111666",
+		"S0=0":       "123666111666",
+		"SS0":        "123123666",
+		"~∀a':a'=a'": "223626262163636262163111262163",
+		"Aa:a=a":     "626262636262111262",
+		"<b=0->c=0>": "212262163111666633262163163111666213",
+		"(d*e)":      "362262163163163236262163163163163323",
+		"b'=c":       "262163163111262163163",
+		")(:":        "323362636",
+	} {
+		got, err := Codons.Encode(src)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", src, err)
+		} else if got.String() != expected {
+			t.Errorf("%q: expected %s but got %s", src, expected, got)
+		}
+	}
+
+	for _, bad := range []string{"", " ", "0=0_", "[0=0]"} {
+		if got, err := Codons.Encode(bad); err == nil {
+			t.Errorf("%q: expected error but got %s", bad, got)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	for digits, expected := range map[string]string{
+		"666111666":                            "0=0",
+		"123666111666":                         "S0=0",
+		"626262636262111262":                   "∀a:a=a",
+		"212262163111666633262163163111666213": "<a'=0⊃a''=0>",
+		"262163163":                            "a''",
+	} {
+		g, _ := new(big.Int).SetString(digits, 10)
+		got, err := Codons.Decode(g)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", digits, err)
+		} else if got != expected {
+			t.Errorf("%s: expected %q but got %q", digits, expected, got)
+		}
+	}
+
+	for _, bad := range []string{"0", "-666", "6661", "666999", "263"} {
+		g, _ := new(big.Int).SetString(bad, 10)
+		if got, err := Codons.Decode(g); err == nil {
+			t.Errorf("%s: expected error but got %q", bad, got)
+		}
+	}
+}
+
+func TestExtendedCodons(t *testing.T) {
+	g, err := ExtendedCodons.Encode("<b'=0->c=0>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "212263163111666633264111666213"; g.String() != expected {
+		t.Errorf("expected %s but got %s", expected, g)
+	}
+	if src, err := ExtendedCodons.Decode(g); err != nil || src != "<b'=0⊃c=0>" {
+		t.Errorf("expected <b'=0⊃c=0> but got %q, %v", src, err)
+	}
+}
+
+func TestAlternativeNumbering(t *testing.T) {
+	numbering := Numbering{'0': 1, 'S': 2, '=': 3}
+	g, err := numbering.Encode("SS0=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.String() != "22131" {
+		t.Errorf("expected 22131 but got %s", g)
+	}
+	src, err := numbering.Decode(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if src != "SS0=0" {
+		t.Errorf("expected SS0=0 but got %q", src)
+	}
+
+	if _, err := numbering.Encode("a=0"); err == nil {
+		t.Error("expected error for symbol without codon")
+	}
+
+	for _, bad := range []Numbering{
+		{},
+		{'0': 1, 'S': 22},
+		{'0': 1, 'S': 1},
+		{'0': 0},
+	} {
+		if _, err := bad.Encode("0"); err == nil {
+			t.Errorf("expected error for numbering %v", bad)
+		}
+	}
+}
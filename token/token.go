@@ -5,7 +5,10 @@
 // order to make input easier.
 package token
 
-import "unicode"
+import (
+	"fmt"
+	"unicode"
+)
 
 type Token int
 
@@ -31,21 +34,87 @@ const (
 	COLON        // :
 	AND          // ∧ ^
 	OR           // ∨ V
-	IF_THEN      // ⊃
+	IF_THEN      // ⊃ ->
+)
+
+// Dialect is an alphabet in which TNT can be written.
+type Dialect int
+
+const (
+	// UNICODE is the notation used in the book.
+	UNICODE Dialect = iota
+	// ASCII uses only characters found on a normal keyboard.
+	ASCII
 )
 
+var symbols = map[Token][2]string{
+	ZERO:         {"0", "0"},
+	SUCCESSOR:    {"S", "S"},
+	OPEN_PAREN:   {"(", "("},
+	CLOSE_PAREN:  {")", ")"},
+	PLUS:         {"+", "+"},
+	MULTIPLY:     {"·", "*"},
+	EQUALS:       {"=", "="},
+	NEGATION:     {"~", "~"},
+	OPEN_ANGLE:   {"<", "<"},
+	CLOSE_ANGLE:  {">", ">"},
+	THERE_EXISTS: {"∃", "E"},
+	FOR_ALL:      {"∀", "A"},
+	COLON:        {":", ":"},
+	AND:          {"∧", "^"},
+	OR:           {"∨", "V"},
+	IF_THEN:      {"⊃", "->"},
+}
+
+// Symbol returns the spelling of the Token in the given Dialect.
+//
+// For SUCCESSOR, a single S is returned.  For ILLEGAL, EOF and VARIABLE,
+// which have no fixed spelling, the empty string is returned.
+func (t Token) Symbol(d Dialect) string {
+	s, ok := symbols[t]
+	if !ok || d < UNICODE || d > ASCII {
+		return ""
+	}
+	return s[d]
+}
+
+// Position is a location in the source of a Scanner.
+type Position struct {
+	Offset int // rune offset, starting at 0
+	Line   int // line number, starting at 1
+	Column int // column number in runes, starting at 1
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
 type Scanner struct {
 	src []rune
 	pos int
+
+	// line and lineStart track the line containing pos
+	line      int
+	lineStart int
+
+	// tokPos is the position of the most recently scanned token
+	tokPos Position
 }
 
 func NewScanner(src string) *Scanner {
 	return &Scanner{
-		src: []rune(src),
-		pos: 0,
+		src:  []rune(src),
+		pos:  0,
+		line: 1,
 	}
 }
 
+// Position returns the position of the first character of the most
+// recently scanned token.  For EOF, this is the end of the source.
+func (s *Scanner) Position() Position {
+	return s.tokPos
+}
+
 // Scan returns the next token in the expression.
 //
 // Both the token type and content are returned, but the content is
@@ -57,12 +126,22 @@ func NewScanner(src string) *Scanner {
 //
 // Once end of file is reached, EOF is returned for all subsequent calls.
 //
-// TODO: return position information for debugging.
+// The position of the returned token is available from Position.
 func (s *Scanner) Scan() (Token, string) {
 	for s.pos < len(s.src) && unicode.IsSpace(s.src[s.pos]) {
+		if s.src[s.pos] == '\n' {
+			s.line++
+			s.lineStart = s.pos + 1
+		}
 		s.pos++
 	}
 
+	s.tokPos = Position{
+		Offset: s.pos,
+		Line:   s.line,
+		Column: s.pos - s.lineStart + 1,
+	}
+
 	if !(s.pos < len(s.src)) {
 		return EOF, ""
 	}
@@ -111,25 +190,29 @@ func (s *Scanner) Scan() (Token, string) {
 	case 'V', '∨':
 		s.pos++
 		return OR, string(ch)
-	case '⊃': // TODO: pick an easier-to-enter alternative character
+	case '⊃':
 		s.pos++
 		return IF_THEN, string(ch)
+	case '-':
+		if s.pos+1 < len(s.src) && s.src[s.pos+1] == '>' {
+			s.pos += 2
+			return IF_THEN, "->"
+		}
+		return ILLEGAL, string(ch)
 	case 'a', 'b', 'c', 'd', 'e':
-		variable := string(ch)
+		start := s.pos
 		s.pos++
 		for s.pos < len(s.src) && s.src[s.pos] == '\'' {
 			s.pos++
-			variable += "'"
 		}
-		return VARIABLE, variable
+		return VARIABLE, string(s.src[start:s.pos])
 	case 'S':
-		successor := "S"
+		start := s.pos
 		s.pos++
 		for s.pos < len(s.src) && s.src[s.pos] == 'S' {
 			s.pos++
-			successor += "S"
 		}
-		return SUCCESSOR, successor
+		return SUCCESSOR, string(s.src[start:s.pos])
 	default:
 		return ILLEGAL, string(ch)
 	}
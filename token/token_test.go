@@ -51,7 +51,7 @@ func TestScanner(t *testing.T) {
 			},
 		},
 		"all single characters": testCase{
-			Input: "0 ( ) + * . · = ~ < > E ∃ A ∀ : ^ ∧ V ∨ ⊃",
+			Input: "0 ( ) + * . · = ~ < > E ∃ A ∀ : ^ ∧ V ∨ ⊃ ->",
 			Expected: []token{
 				{ZERO, "0"},
 				{OPEN_PAREN, "("},
@@ -74,6 +74,17 @@ func TestScanner(t *testing.T) {
 				{OR, "V"},
 				{OR, "∨"},
 				{IF_THEN, "⊃"},
+				{IF_THEN, "->"},
+			},
+		},
+		"incomplete arrow": testCase{
+			Input: "<0=0-0=0>",
+			Expected: []token{
+				{OPEN_ANGLE, "<"},
+				{ZERO, "0"},
+				{EQUALS, "="},
+				{ZERO, "0"},
+				{ILLEGAL, "-"},
 			},
 		},
 		"variables": testCase{
@@ -147,3 +158,53 @@ func TestScanner(t *testing.T) {
 		})
 	}
 }
+
+func TestSymbolsRescan(t *testing.T) {
+	for _, d := range []Dialect{UNICODE, ASCII} {
+		for tok := ZERO; tok <= IF_THEN; tok++ {
+			if tok == VARIABLE {
+				continue
+			}
+			symbol := tok.Symbol(d)
+			got, value := NewScanner(symbol).Scan()
+			if got != tok || value != symbol {
+				t.Errorf("dialect %d: expected symbol %q to scan as %s, "+
+					"but got %s %q", d, symbol, tok, got, value)
+			}
+		}
+	}
+
+	if s := IF_THEN.Symbol(ASCII); s != "->" {
+		t.Errorf("expected ASCII IF_THEN to be \"->\" but got %q", s)
+	}
+	for _, tok := range []Token{ILLEGAL, EOF, VARIABLE} {
+		if s := tok.Symbol(UNICODE); s != "" {
+			t.Errorf("expected no symbol for %s but got %q", tok, s)
+		}
+	}
+}
+
+func TestScannerPosition(t *testing.T) {
+	s := NewScanner("a=b\n  ∀c:\n\n~Sd")
+	for _, expected := range []struct {
+		Token    Token
+		Position Position
+	}{
+		{VARIABLE, Position{0, 1, 1}},
+		{EQUALS, Position{1, 1, 2}},
+		{VARIABLE, Position{2, 1, 3}},
+		{FOR_ALL, Position{6, 2, 3}},
+		{VARIABLE, Position{7, 2, 4}},
+		{COLON, Position{8, 2, 5}},
+		{NEGATION, Position{11, 4, 1}},
+		{SUCCESSOR, Position{12, 4, 2}},
+		{VARIABLE, Position{13, 4, 3}},
+		{EOF, Position{14, 4, 4}},
+	} {
+		tok, _ := s.Scan()
+		if tok != expected.Token || s.Position() != expected.Position {
+			t.Fatalf("expected %s at %+v but got %s at %+v",
+				expected.Token, expected.Position, tok, s.Position())
+		}
+	}
+}
@@ -68,6 +68,16 @@ func (v VariableSet) String() string {
 	return fmt.Sprintf("%s", slice)
 }
 
+// sorted returns the elements of v in order.
+func (v VariableSet) sorted() []Variable {
+	slice := make([]Variable, 0, len(v))
+	for k := range v {
+		slice = append(slice, k)
+	}
+	sort.Slice(slice, func(i, j int) bool { return slice[i] < slice[j] })
+	return slice
+}
+
 func (v VariableSet) add(v2 VariableSet) {
 	for item := range v2 {
 		v[item] = struct{}{}
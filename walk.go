@@ -0,0 +1,195 @@
+package tnt
+
+import "fmt"
+
+// Node is a Term or a Formula.
+type Node interface {
+	Variables() VariableSet
+	String() string
+}
+
+// isTerm returns true if n is one of the Term types.  Since every
+// Formula also satisfies the Term interface, a type assertion to Term
+// is not sufficient.
+func isTerm(n Node) bool {
+	switch n.(type) {
+	case Numeral, Variable, Successor, CompoundTerm:
+		return true
+	}
+	return false
+}
+
+// branches returns the Branches that lead from n to its parts, in order.
+func branches(n Node) []Branch {
+	switch n.(type) {
+	case Successor, Negation, Quantification:
+		return []Branch{BODY}
+	case CompoundTerm, Atom, Compound:
+		return []Branch{LEFT, RIGHT}
+	}
+	return nil
+}
+
+// child returns the part of n selected by b, or nil if there is none.
+func child(n Node, b Branch) Node {
+	switch n := n.(type) {
+	case Successor:
+		if b == BODY {
+			return n.Term
+		}
+	case CompoundTerm:
+		switch b {
+		case LEFT:
+			return n.Left
+		case RIGHT:
+			return n.Right
+		}
+	case Atom:
+		switch b {
+		case LEFT:
+			return n.Left
+		case RIGHT:
+			return n.Right
+		}
+	case Negation:
+		if b == BODY {
+			return n.Formula
+		}
+	case Compound:
+		switch b {
+		case LEFT:
+			return n.Left
+		case RIGHT:
+			return n.Right
+		}
+	case Quantification:
+		if b == BODY {
+			return n.Formula
+		}
+	}
+	return nil
+}
+
+// withChild returns a copy of n with the part selected by b replaced
+// by c.  An error is returned if n has no such part, or if c is a Term
+// where a Formula is required or vice versa.
+func withChild(n Node, b Branch, c Node) (Node, error) {
+	if child(n, b) == nil {
+		return nil, fmt.Errorf("%s has no %s", n, b)
+	}
+
+	if isTerm(n) || isTerm(child(n, b)) {
+		t, ok := c.(Term)
+		if !ok || !isTerm(c) {
+			return nil, fmt.Errorf("cannot replace a term with formula %s", c)
+		}
+		switch n := n.(type) {
+		case Successor:
+			n.Term = t
+			return n, nil
+		case CompoundTerm:
+			if b == LEFT {
+				n.Left = t
+			} else {
+				n.Right = t
+			}
+			return n, nil
+		case Atom:
+			if b == LEFT {
+				n.Left = t
+			} else {
+				n.Right = t
+			}
+			return n, nil
+		}
+	}
+
+	f, ok := c.(Formula)
+	if !ok {
+		return nil, fmt.Errorf("cannot replace a formula with term %s", c)
+	}
+	switch n := n.(type) {
+	case Negation:
+		n.Formula = f
+		return n, nil
+	case Compound:
+		if b == LEFT {
+			n.Left = f
+		} else {
+			n.Right = f
+		}
+		return n, nil
+	case Quantification:
+		n.Formula = f
+		return n, nil
+	}
+	return nil, fmt.Errorf("%s has no %s", n, b)
+}
+
+// A Visitor's Visit method is invoked for each Node encountered by Walk.
+// If the result visitor w is not nil, Walk visits each of the parts of
+// node with the visitor w, followed by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses a Term or Formula in depth-first order: It starts by
+// calling v.Visit(node); node must not be nil.  If the visitor w
+// returned by v.Visit(node) is not nil, Walk is invoked recursively
+// with visitor w for each of the parts of node, from left to right,
+// followed by a call of w.Visit(nil).
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+	for _, b := range branches(node) {
+		Walk(v, child(node, b))
+	}
+	v.Visit(nil)
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses a Term or Formula in depth-first order: It starts by
+// calling f(node); node must not be nil.  If f returns true, Inspect
+// invokes f recursively for each of the parts of node, followed by a
+// call of f(nil).
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
+
+// Rewrite rebuilds f from the bottom up.  Each Node is first rebuilt
+// from its rewritten parts, and then passed to fn, whose result takes
+// its place.  fn may return its argument unchanged.
+//
+// Terms and Formulas are never modified in place.  Successors are not
+// folded together after rewriting; use Equal to compare the result.
+//
+// Rewrite panics if fn returns a Term in place of a Formula or vice
+// versa.
+func Rewrite(f Formula, fn func(Node) Node) Formula {
+	rewritten := rewrite(f, fn)
+	if isTerm(rewritten) {
+		panic(fmt.Sprintf("tnt: Rewrite replaced formula %s with term %s",
+			f, rewritten))
+	}
+	return rewritten.(Formula)
+}
+
+func rewrite(n Node, fn func(Node) Node) Node {
+	for _, b := range branches(n) {
+		var err error
+		n, err = withChild(n, b, rewrite(child(n, b), fn))
+		if err != nil {
+			panic("tnt: Rewrite: " + err.Error())
+		}
+	}
+	return fn(n)
+}
@@ -0,0 +1,115 @@
+package tnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+type recorder struct {
+	visited *[]string
+}
+
+func (r recorder) Visit(node Node) Visitor {
+	if node == nil {
+		*r.visited = append(*r.visited, "end")
+		return nil
+	}
+	*r.visited = append(*r.visited, node.String())
+	if _, ok := node.(Atom); ok {
+		// don't descend into terms
+		return nil
+	}
+	return r
+}
+
+func TestWalk(t *testing.T) {
+	formula, err := ParseFormula("∀a:<~a=0∨Sa=S0>")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var visited []string
+	Walk(recorder{&visited}, formula)
+
+	expected := []string{
+		"∀a:<~a=0∨Sa=S0>",
+		"<~a=0∨Sa=S0>",
+		"~a=0",
+		"a=0",
+		"end", // ~a=0
+		"Sa=S0",
+		"end", // <~a=0∨Sa=S0>
+		"end", // ∀a:<~a=0∨Sa=S0>
+	}
+	if !reflect.DeepEqual(visited, expected) {
+		t.Errorf("expected to visit %q but visited %q", expected, visited)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	formula, err := ParseFormula("<(a+S0)=b∧∃c:(c·b)=0>")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var terms []string
+	Inspect(formula, func(n Node) bool {
+		if n != nil && isTerm(n) {
+			terms = append(terms, n.String())
+		}
+		return true
+	})
+
+	expected := []string{"(a+S0)", "a", "S0", "b", "(c·b)", "c", "b", "0"}
+	if !reflect.DeepEqual(terms, expected) {
+		t.Errorf("expected terms %q but got %q", expected, terms)
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	input := "∀a:<(a+0)=a⊃~(0·a)=Sb>"
+	formula, err := ParseFormula(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// swap the operands of every compound term, and replace b with 0
+	rewritten := Rewrite(formula, func(n Node) Node {
+		switch n := n.(type) {
+		case CompoundTerm:
+			n.Left, n.Right = n.Right, n.Left
+			return n
+		case Variable:
+			if n == "b" {
+				return Numeral(0)
+			}
+		}
+		return n
+	})
+
+	if got, expected := rewritten.String(), "∀a:<(0+a)=a⊃~(a·0)=S0>"; got != expected {
+		t.Errorf("expected %q but got %q", expected, got)
+	}
+	if got := formula.String(); got != input {
+		t.Errorf("expected original to be unmodified, but got %q", got)
+	}
+}
+
+func TestRewriteWrongKind(t *testing.T) {
+	formula, err := ParseFormula("~a=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	Rewrite(formula, func(n Node) Node {
+		if _, ok := n.(Atom); ok {
+			return Numeral(0)
+		}
+		return n
+	})
+}
@@ -0,0 +1,141 @@
+package tnt
+
+import "fmt"
+
+// Violation is a rule of formation from Chapter 8 that a Formula breaks.
+type Violation int
+
+//go:generate stringer -type Violation
+
+const (
+	// FREE_AND_QUANTIFIED is a Compound with a Variable that is free in
+	// one of the contained Formulas and quantified in the other.
+	FREE_AND_QUANTIFIED Violation = iota
+	// NOT_FREE is a Quantification of a Variable that is not free in
+	// the contained Formula.
+	NOT_FREE
+)
+
+// Diagnostic explains why a Formula is not well formed.
+type Diagnostic struct {
+	Violation Violation
+	Variable  Variable
+	// Path locates the Compound or Quantification that breaks the rule.
+	Path Path
+	// Free locates a free occurrence of Variable, or is nil if there is
+	// none.
+	Free Path
+	// Bound locates a Quantification of Variable, or is nil if there is
+	// none.
+	Bound Path
+}
+
+func (d Diagnostic) String() string {
+	switch d.Violation {
+	case FREE_AND_QUANTIFIED:
+		return fmt.Sprintf("%s: %s is free at %s but quantified at %s; "+
+			"a variable which is free in one half of a compound "+
+			"may not be quantified in the other",
+			d.Path, d.Variable, d.Free, d.Bound)
+	case NOT_FREE:
+		reason := "does not occur"
+		if d.Bound != nil {
+			reason = fmt.Sprintf("is already quantified at %s", d.Bound)
+		}
+		return fmt.Sprintf("%s: %s %s; a variable may only be quantified "+
+			"in a formula in which it is free",
+			d.Path, d.Variable, reason)
+	}
+	return fmt.Sprintf("%s: %s: %s", d.Path, d.Variable, d.Violation)
+}
+
+// CheckWellFormed returns a Diagnostic for every rule of formation that
+// the Formula or any Formula inside it breaks.  A Formula is WellFormed
+// if and only if there are none.
+func CheckWellFormed(f Formula) []Diagnostic {
+	return checkWellFormed(f, Path{})
+}
+
+func checkWellFormed(f Formula, path Path) []Diagnostic {
+	switch f := f.(type) {
+	case Negation:
+		return checkWellFormed(f.Formula, path.Child(BODY))
+	case Compound:
+		diags := append(
+			checkWellFormed(f.Left, path.Child(LEFT)),
+			checkWellFormed(f.Right, path.Child(RIGHT))...)
+
+		check := func(free, quantified Formula, freeBranch, quantifiedBranch Branch) {
+			clashes := free.FreeVariables().Intersection(quantifiedVariables(quantified))
+			for _, v := range clashes.sorted() {
+				diags = append(diags, Diagnostic{
+					Violation: FREE_AND_QUANTIFIED,
+					Variable:  v,
+					Path:      path,
+					Free:      findFree(free, v, path.Child(freeBranch)),
+					Bound:     findQuantification(quantified, v, path.Child(quantifiedBranch)),
+				})
+			}
+		}
+		check(f.Left, f.Right, LEFT, RIGHT)
+		check(f.Right, f.Left, RIGHT, LEFT)
+
+		return diags
+	case Quantification:
+		diags := checkWellFormed(f.Formula, path.Child(BODY))
+		if _, ok := f.Formula.FreeVariables()[f.Variable]; !ok {
+			diags = append(diags, Diagnostic{
+				Violation: NOT_FREE,
+				Variable:  f.Variable,
+				Path:      path,
+				Bound:     findQuantification(f.Formula, f.Variable, path.Child(BODY)),
+			})
+		}
+		return diags
+	}
+	return nil
+}
+
+// quantifiedVariables returns the Variables of every Quantification
+// inside f.
+func quantifiedVariables(f Formula) VariableSet {
+	quantified := make(VariableSet)
+	Inspect(f, func(n Node) bool {
+		if q, ok := n.(Quantification); ok {
+			quantified[q.Variable] = struct{}{}
+		}
+		return true
+	})
+	return quantified
+}
+
+// findQuantification returns the Path of the first Quantification of v
+// in f, or nil if there is none.  path is the Path of f.
+func findQuantification(f Formula, v Variable, path Path) Path {
+	switch f := f.(type) {
+	case Negation:
+		return findQuantification(f.Formula, v, path.Child(BODY))
+	case Compound:
+		if found := findQuantification(f.Left, v, path.Child(LEFT)); found != nil {
+			return found
+		}
+		return findQuantification(f.Right, v, path.Child(RIGHT))
+	case Quantification:
+		if f.Variable == v {
+			return path
+		}
+		return findQuantification(f.Formula, v, path.Child(BODY))
+	}
+	return nil
+}
+
+// findFree returns the Path of the first free occurrence of v in f, or
+// nil if there is none.  path is the Path of f.
+func findFree(f Formula, v Variable, path Path) Path {
+	for _, o := range Occurrences(f) {
+		if o.Variable == v && o.Free() {
+			return append(path[:len(path):len(path)], o.Path...)
+		}
+	}
+	return nil
+}
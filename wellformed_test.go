@@ -0,0 +1,108 @@
+package tnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckWellFormed(t *testing.T) {
+	for input, expected := range map[string][]Diagnostic{
+		"0=0":             nil,
+		"Aa:Eb:<a=b^b=c>": nil,
+		"<Aa:a=a^a=a>": {{
+			Violation: FREE_AND_QUANTIFIED,
+			Variable:  "a",
+			Path:      Path{},
+			Free:      Path{RIGHT, LEFT},
+			Bound:     Path{LEFT},
+		}},
+		"~<a=a^Aa:a=a>": {{
+			Violation: FREE_AND_QUANTIFIED,
+			Variable:  "a",
+			Path:      Path{BODY},
+			Free:      Path{BODY, LEFT, LEFT},
+			Bound:     Path{BODY, RIGHT},
+		}},
+		"Eb:<Aa:a=a^~S(0+a)=b>": {{
+			Violation: FREE_AND_QUANTIFIED,
+			Variable:  "a",
+			Path:      Path{BODY},
+			Free:      Path{BODY, RIGHT, BODY, LEFT, BODY, RIGHT},
+			Bound:     Path{BODY, LEFT},
+		}},
+		"Aa:0=0": {{
+			Violation: NOT_FREE,
+			Variable:  "a",
+			Path:      Path{},
+		}},
+		"Ea:Aa:a=0": {{
+			Violation: NOT_FREE,
+			Variable:  "a",
+			Path:      Path{},
+			Bound:     Path{BODY},
+		}},
+		"<Ab:Ea:a=b^Ac:<a=c^c=b>>": {
+			{
+				Violation: FREE_AND_QUANTIFIED,
+				Variable:  "a",
+				Path:      Path{},
+				Free:      Path{RIGHT, BODY, LEFT, LEFT},
+				Bound:     Path{LEFT, BODY},
+			},
+			{
+				Violation: FREE_AND_QUANTIFIED,
+				Variable:  "b",
+				Path:      Path{},
+				Free:      Path{RIGHT, BODY, RIGHT, RIGHT},
+				Bound:     Path{LEFT},
+			},
+		},
+		// b is bound on both sides, but a is free on the right
+		"<Ab:Ea:a=b^Ac:<a=c^Ab:b=a>>": {{
+			Violation: FREE_AND_QUANTIFIED,
+			Variable:  "a",
+			Path:      Path{},
+			Free:      Path{RIGHT, BODY, LEFT, LEFT},
+			Bound:     Path{LEFT, BODY},
+		}},
+	} {
+		formula, err := ParseFormula(input)
+		if err != nil {
+			t.Errorf("error parsing %q: %s", input, err)
+			continue
+		}
+		got := CheckWellFormed(formula)
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("%q: expected diagnostics %v but got %v",
+				input, expected, got)
+		}
+		if formula.WellFormed() != (len(expected) == 0) {
+			t.Errorf("%q: WellFormed disagrees with diagnostics %v",
+				input, got)
+		}
+	}
+}
+
+func TestDiagnosticString(t *testing.T) {
+	for input, expected := range map[string]string{
+		"<Aa:a=a^a=a>": "/: a is free at /RIGHT/LEFT but quantified at /LEFT; " +
+			"a variable which is free in one half of a compound " +
+			"may not be quantified in the other",
+		"Aa:0=0": "/: a does not occur; a variable may only be quantified " +
+			"in a formula in which it is free",
+		"~Ea:Aa:a=0": "/BODY: a is already quantified at /BODY/BODY; " +
+			"a variable may only be quantified in a formula in which it is free",
+	} {
+		formula, err := ParseFormula(input)
+		if err != nil {
+			t.Errorf("error parsing %q: %s", input, err)
+			continue
+		}
+		diags := CheckWellFormed(formula)
+		if len(diags) != 1 {
+			t.Errorf("%q: expected one diagnostic but got %v", input, diags)
+		} else if got := diags[0].String(); got != expected {
+			t.Errorf("%q: expected %q but got %q", input, expected, got)
+		}
+	}
+}